@@ -0,0 +1,78 @@
+package browsers
+
+import (
+	"context"
+	"github.com/luoxk/chromedp"
+	"time"
+)
+
+// ActionBuilder 以链式调用累积一组操作，Run 时在一次 chromedp.Run 中依次执行
+type ActionBuilder struct {
+	bi      *BrowserInstance
+	actions []chromedp.Action
+}
+
+// Do 创建一个操作构建器，例如 bi.Do().Navigate(url).WaitVisible(sel).Click(sel).Run()
+func (bi *BrowserInstance) Do() *ActionBuilder {
+	return &ActionBuilder{bi: bi}
+}
+
+// Navigate 导航到指定地址
+func (ab *ActionBuilder) Navigate(url string) *ActionBuilder {
+	return ab.Action(chromedp.Navigate(url))
+}
+
+// WaitVisible 等待元素可见
+func (ab *ActionBuilder) WaitVisible(sel string) *ActionBuilder {
+	return ab.Action(chromedp.WaitVisible(sel, chromedp.ByQuery))
+}
+
+// WaitReady 等待元素出现在 DOM 中
+func (ab *ActionBuilder) WaitReady(sel string) *ActionBuilder {
+	return ab.Action(chromedp.WaitReady(sel, chromedp.ByQuery))
+}
+
+// Click 点击元素
+func (ab *ActionBuilder) Click(sel string) *ActionBuilder {
+	return ab.Action(chromedp.Click(sel, chromedp.ByQuery))
+}
+
+// SendKeys 向元素输入文本
+func (ab *ActionBuilder) SendKeys(sel, text string) *ActionBuilder {
+	return ab.Action(chromedp.SendKeys(sel, text, chromedp.ByQuery))
+}
+
+// SetValue 直接设置元素的 value
+func (ab *ActionBuilder) SetValue(sel, value string) *ActionBuilder {
+	return ab.Action(chromedp.SetValue(sel, value, chromedp.ByQuery))
+}
+
+// Evaluate 执行 JS 表达式，结果写入 res，res 为 nil 时忽略结果
+func (ab *ActionBuilder) Evaluate(expr string, res interface{}) *ActionBuilder {
+	return ab.Action(chromedp.Evaluate(expr, res))
+}
+
+// Sleep 等待固定时长
+func (ab *ActionBuilder) Sleep(d time.Duration) *ActionBuilder {
+	return ab.Action(chromedp.Sleep(d))
+}
+
+// Func 追加一个自定义操作，fn 中应直接使用 ctx 执行 chromedp 操作，不要调用实例的 Goto、SabaFetch 等方法，否则会死锁
+func (ab *ActionBuilder) Func(fn func(ctx context.Context) error) *ActionBuilder {
+	return ab.Action(chromedp.ActionFunc(fn))
+}
+
+// Action 追加任意 chromedp.Action
+func (ab *ActionBuilder) Action(action chromedp.Action) *ActionBuilder {
+	ab.actions = append(ab.actions, action)
+	return ab
+}
+
+// Run 执行累积的全部操作，遇到第一个错误即停止并返回
+func (ab *ActionBuilder) Run() error {
+	if ab.bi.Closed() {
+		return ErrBrowserClosed
+	}
+	defer ab.bi.acquire()()
+	return chromedp.Run(ab.bi.Ctx, ab.actions...)
+}
@@ -0,0 +1,46 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/luoxk/chromedp"
+	"github.com/mailru/easyjson"
+)
+
+// ExecuteCDP 在当前实例的标签页上发送任意 CDP 命令
+// params 和 res 可以是 cdproto 中的类型，也可以是任何可被 encoding/json 处理的值，传 nil 表示无参数或忽略结果
+func (bi *BrowserInstance) ExecuteCDP(method string, params interface{}, res interface{}) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	var marshaler easyjson.Marshaler
+	switch p := params.(type) {
+	case nil:
+	case easyjson.Marshaler:
+		marshaler = p
+	default:
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("序列化 %s 参数失败: %v", method, err)
+		}
+		msg := easyjson.RawMessage(raw)
+		marshaler = &msg
+	}
+
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if res == nil {
+			return cdp.Execute(ctx, method, marshaler, nil)
+		}
+		if u, ok := res.(easyjson.Unmarshaler); ok {
+			return cdp.Execute(ctx, method, marshaler, u)
+		}
+		var raw easyjson.RawMessage
+		if err := cdp.Execute(ctx, method, marshaler, &raw); err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, res)
+	}))
+}
@@ -0,0 +1,20 @@
+package browsers
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// killWithParent 与 chromedp 默认的命令设置相同：Go 进程退出时由内核向 Chrome 发送 SIGKILL
+// 设置了 ModifyCmd 后 chromedp 不再应用默认设置，需要在这里补上
+func killWithParent(cmd *exec.Cmd) {
+	if _, ok := os.LookupEnv("LAMBDA_TASK_ROOT"); ok {
+		// 与 chromedp 一致，AWS Lambda 上不设置
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Pdeathsig = syscall.SIGKILL
+}
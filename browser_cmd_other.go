@@ -0,0 +1,8 @@
+//go:build !linux
+
+package browsers
+
+import "os/exec"
+
+// killWithParent 只有 Linux 支持 Pdeathsig，其他系统上 chromedp 的默认设置也为空
+func killWithParent(cmd *exec.Cmd) {}
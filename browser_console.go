@@ -0,0 +1,73 @@
+package browsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/runtime"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// consoleMu 串行化所有实例的 console 写入，同一个 writer 通常被多个实例共用
+var consoleMu sync.Mutex
+
+// consoleWriter 将页面的 console 输出和未捕获的异常逐行写入 w
+type consoleWriter struct {
+	w  io.Writer
+	id int
+}
+
+func newConsoleWriter(w io.Writer, id int) *consoleWriter {
+	return &consoleWriter{w: w, id: id}
+}
+
+// handle 作为 ListenTarget 的回调，格式为：时间 [实例 ID] [级别] 内容 (来源位置)
+func (c *consoleWriter) handle(ev interface{}) {
+	switch ev := ev.(type) {
+	case *runtime.EventConsoleAPICalled:
+		args := make([]string, 0, len(ev.Args))
+		for _, arg := range ev.Args {
+			args = append(args, formatRemoteObject(arg))
+		}
+		c.write(string(ev.Type), strings.Join(args, " "), ev.StackTrace)
+	case *runtime.EventExceptionThrown:
+		d := ev.ExceptionDetails
+		text := d.Text
+		if d.Exception != nil && d.Exception.Description != "" {
+			text = d.Exception.Description
+		}
+		c.write("exception", text, d.StackTrace)
+	}
+}
+
+func (c *consoleWriter) write(level, text string, stack *runtime.StackTrace) {
+	line := fmt.Sprintf("%s [%d] [%s] %s", time.Now().Format("15:04:05.000"), c.id, level, text)
+	if stack != nil && len(stack.CallFrames) > 0 {
+		f := stack.CallFrames[0]
+		// CDP 的行号和列号从 0 开始
+		line += fmt.Sprintf(" (%s:%d:%d)", f.URL, f.LineNumber+1, f.ColumnNumber+1)
+	}
+	consoleMu.Lock()
+	defer consoleMu.Unlock()
+	io.WriteString(c.w, line+"\n")
+}
+
+// formatRemoteObject 将 console 参数转换为可读文本，字符串原样输出，其他值使用 JSON 或对象描述
+func formatRemoteObject(o *runtime.RemoteObject) string {
+	if len(o.Value) > 0 {
+		var s string
+		if json.Unmarshal(o.Value, &s) == nil {
+			return s
+		}
+		return string(o.Value)
+	}
+	if o.UnserializableValue != "" {
+		return string(o.UnserializableValue)
+	}
+	if o.Description != "" {
+		return o.Description
+	}
+	return string(o.Type)
+}
@@ -0,0 +1,33 @@
+package browsers
+
+import (
+	"bytes"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/mailru/easyjson"
+	"strings"
+	"testing"
+)
+
+func TestConsoleWriter(t *testing.T) {
+	var buf bytes.Buffer
+	c := newConsoleWriter(&buf, 7)
+	c.handle(&runtime.EventConsoleAPICalled{
+		Type: runtime.APITypeWarning,
+		Args: []*runtime.RemoteObject{
+			{Type: runtime.TypeString, Value: easyjson.RawMessage(`"token"`)},
+			{Type: runtime.TypeNumber, Value: easyjson.RawMessage(`42`)},
+			{Type: runtime.TypeObject, Description: "Object"},
+		},
+		StackTrace: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{
+			{URL: "https://example.com/app.js", LineNumber: 9, ColumnNumber: 4},
+		}},
+	})
+
+	line := buf.String()
+	if !strings.Contains(line, "[7] [warning] token 42 Object (https://example.com/app.js:10:5)") {
+		t.Fatalf("格式不正确: %q", line)
+	}
+	if !strings.HasSuffix(line, "\n") {
+		t.Fatalf("每条记录应以换行结尾: %q", line)
+	}
+}
@@ -2,12 +2,43 @@ package browsers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/security"
 	"github.com/luoxk/chromedp"
 	"image"
+	"io"
 	"log"
+	"os"
+	"os/exec"
+	"strings"
 	"sync"
+	"time"
+)
+
+// ErrProfileLocked 表示 UserDir 已被另一个 Chrome 进程占用
+var ErrProfileLocked = errors.New("browser profile directory is locked by another process")
+
+// ErrDraining 表示控制器已调用 Drain，不再启动新的实例
+var ErrDraining = errors.New("browser controller is draining")
+
+// profileLockedMarkers 是 Chrome 在用户目录被占用时输出的已知错误文本
+var profileLockedMarkers = []string{
+	"profile appears to be in use",
+	"ProcessSingleton",
+	"SingletonLock",
+	"Opening in existing browser session",
+}
+
+// HeadlessMode 无头模式类型
+type HeadlessMode int
+
+const (
+	HeadlessModeDefault HeadlessMode = iota // 未指定，由 BrowserOptions.Headless 决定
+	HeadlessModeOff                         // 有界面模式
+	HeadlessModeLegacy                      // 旧版无头模式，即 --headless
+	HeadlessModeNew                         // 新版无头模式，即 --headless=new，与有界面模式渲染一致
 )
 
 // BrowserOptions 用于配置浏览器启动参数
@@ -21,39 +52,396 @@ type BrowserOptions struct {
 	HookFunc    func(ctx context.Context) func(event interface{}) // 网络拦截器
 	WindowSize  *image.Point                                      //窗口大小
 	DisableGPU  bool                                              //禁用硬件加速
+
+	SkipInitialNavigate bool // 启动时跳过 about:blank 导航，仅创建标签页
+	InjectInstanceID    bool // 向页面注入 window.__browserInstanceId，便于关联日志
+	CloneUserDir        bool // 将 UserDir 复制到临时目录后再启动，关闭时删除副本
+
+	Labels map[string]string // 实例标签，用于按账号、任务等维度分组管理
+
+	HeadlessMode     HeadlessMode  // 无头模式类型，设置后覆盖 Headless
+	IgnoreCertErrors bool          // 忽略证书错误，用于自签名证书的内部站点
+	DefaultTimeout   time.Duration // 实例操作的默认超时，为 0 时不限制
+
+	// ResponseMocks 启动时注册的模拟响应，键为 URL 匹配模式（子串或 * 通配符）
+	// 与 HookFunc 同时使用时，未匹配的请求交给 HookFunc 处理
+	ResponseMocks map[string]MockResponse
+
+	DownloadDir string // 下载文件的保存目录，为空时首次等待下载时创建临时目录
+
+	UserAgentRotator func() string // 每次 Goto 前调用，返回非空时用于覆盖 UA
+	InitialURL       string        // 启动后直接导航的地址，为空时导航到 about:blank
+
+	Env []string // 浏览器进程额外的环境变量，格式为 KEY=value，如 DISPLAY=:99
+
+	// RemoteURL 远程浏览器的 DevTools 地址（ws:// 或 http://），设置后不再启动本地进程，
+	// Path、Headless、Flags 等本地启动参数将被忽略；每个实例在独立的浏览器上下文中运行，关闭实例不会关闭远程浏览器
+	RemoteURL string
+
+	// BaseAllocatorOptions 非 nil 时代替 chromedp.DefaultExecAllocatorOptions 作为基础启动参数，
+	// 用于去掉 --no-sandbox 等不需要的默认参数；Flags 等其他设置仍会追加在其后
+	BaseAllocatorOptions []chromedp.ExecAllocatorOption
+
+	AutoAcceptBeforeUnload bool // 自动确认 beforeunload 对话框，避免 Goto、Close 被页面拦截
+
+	// InterceptResponses 使用 HookFunc 时让请求在响应阶段再暂停一次，
+	// 以便通过 GetResponseBodyForInterception 查看真实响应后再决定替换或放行
+	InterceptResponses bool
+
+	// DetachOnExit 使 Chrome 在 Go 进程退出或崩溃后继续运行，便于事后查看页面状态。
+	// 显式 Close 仍会关闭浏览器；但未关闭的实例会一直占用进程、内存和端口，
+	// 未指定 UserDir 时 chromedp 创建的临时用户目录也不会被删除，需要手动清理。仅用于调试
+	// 开启后不再设置 chromedp 默认的 Pdeathsig（Linux 上随 Go 进程退出而杀死 Chrome）
+	DetachOnExit bool
+
+	// ModifyCmd 启动本地 Chrome 前修改 exec.Cmd，在 DetachOnExit 或 chromedp 默认的进程设置之后执行。
+	// chromedp 只保留最后一个 ModifyCmdFunc，设置了 ModifyCmd 或 DetachOnExit 时，
+	// Flags 和 BaseAllocatorOptions 中的 chromedp.ModifyCmdFunc 会被覆盖，需要改用这里
+	ModifyCmd func(cmd *exec.Cmd)
+
+	// DisableImages 通过 --blink-settings=imagesEnabled=false 禁止加载图片，无需启用 Fetch 域，开销比拦截小。
+	// 与在 HookFunc 中按资源类型拦截图片的做法互斥，不要同时使用；Flags 中也不要再设置 blink-settings，否则会相互覆盖
+	DisableImages bool
+
+	Referer string // Goto 默认携带的 Referer，GotoWithReferer 可以按次指定
+
+	ConsoleWriter io.Writer // 非 nil 时将页面的 console 输出和未捕获的异常实时写入，每条一行，含级别和来源位置
 }
 
 // BrowserController 用于管理多个浏览器实例
 type BrowserController struct {
 	instances map[int]*BrowserInstance // 浏览器实例的映射
+	children  map[int][]int            // 父实例 ID 到 LaunchIsolated 创建的子实例 ID
 	nextID    int                      // 下一个浏览器实例的 ID
 	mu        sync.Mutex               // 用于保护 instances 和 nextID 的互斥锁
+
+	LaunchRateLimit time.Duration // 两次启动之间的最小间隔，为 0 时不限制；超出频率的调用排队等待
+
+	rateMu     sync.Mutex // 保护 nextLaunch
+	nextLaunch time.Time  // 下一次允许启动的时间
+
+	MaxMemoryMB         int           // 单个实例（含子进程）的内存上限，超出后由后台巡检关闭并按原参数以新 ID 重启（见 OnRecycle），为 0 时不巡检
+	MemoryCheckInterval time.Duration // 内存巡检间隔，为 0 时使用 defaultMemoryCheckInterval
+
+	// OnRecycle 内存超限的实例被关闭并重启后调用，old 为已关闭的旧实例，replacement 为以新 ID 启动的替代实例
+	// 回收后调用方持有的旧实例指针全部失效，需要在这里换成 replacement。
+	// BrowserPool 的实例只在空闲时回收，替代实例由池自动放回空闲队列，借出中的实例不会被回收；
+	// 旧实例通过 LaunchIsolated 创建的子实例随之关闭，不会重启，也不会触发 OnRecycle
+	OnRecycle func(old, replacement *BrowserInstance)
+
+	watching bool // 内存巡检是否在运行，由 mu 保护
+	draining bool // 是否已调用 Drain，由 mu 保护
+
+	ProxyList []string // 代理地址列表，LaunchBrowser 的 options.Proxy 为空时按顺序轮流使用，需在启动实例前设置
+	nextProxy int      // 下一次使用的 ProxyList 下标，由 mu 保护
 }
 
+// defaultMemoryCheckInterval 内存巡检的默认间隔
+const defaultMemoryCheckInterval = 30 * time.Second
+
 // NewBrowserController 创建一个新的 BrowserController 实例
 func NewBrowserController() *BrowserController {
 	return &BrowserController{
 		instances: make(map[int]*BrowserInstance),
+		children:  make(map[int][]int),
 		nextID:    1, // 从 1 开始分配 ID
 	}
 }
 
 // LaunchBrowser 启动一个新的浏览器实例
 func (bc *BrowserController) LaunchBrowser(options BrowserOptions) (*BrowserInstance, error) {
+	if err := validateEnv(options.Env); err != nil {
+		return nil, err
+	}
+	bc.waitLaunchSlot()
+
+	// 只在分配 ID 和登记实例时加锁，多个浏览器可以并行启动
 	bc.mu.Lock()
-	defer bc.mu.Unlock()
+	if bc.draining {
+		bc.mu.Unlock()
+		return nil, ErrDraining
+	}
+	id := bc.nextID
+	bc.nextID++
+	if options.Proxy == "" && options.RemoteURL == "" && len(bc.ProxyList) > 0 {
+		options.Proxy = bc.ProxyList[bc.nextProxy%len(bc.ProxyList)]
+		bc.nextProxy = (bc.nextProxy + 1) % len(bc.ProxyList)
+	}
+	bc.mu.Unlock()
+
+	// 创建上下文，设置了 RemoteURL 时连接已有的浏览器，否则启动本地进程
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var clonedDir string
+	var contextOpts []chromedp.ContextOption
+	if options.RemoteURL != "" {
+		warnRemoteIgnored(options)
+		ctx, cancel = chromedp.NewRemoteAllocator(context.Background(), options.RemoteURL)
+		// 远程浏览器可能被多个实例共享，每个实例使用独立的浏览器上下文，关闭时只销毁自己的上下文，cookie 也互不影响
+		contextOpts = append(contextOpts, chromedp.WithNewBrowserContext())
+	} else {
+		allocatorOpts, dir, err := execAllocatorOptions(options)
+		if err != nil {
+			return nil, err
+		}
+		clonedDir = dir
+		ctx, cancel = chromedp.NewExecAllocator(context.Background(), allocatorOpts...)
+	}
+	ctx, cancel = chromedp.NewContext(ctx, contextOpts...)
+	if clonedDir != "" {
+		// cancel 会等待浏览器进程退出，之后再删除临时目录
+		browserCancel := cancel
+		cancel = func() {
+			browserCancel()
+			if err := os.RemoveAll(clonedDir); err != nil {
+				log.Printf("Failed to remove cloned user dir %s: %v", clonedDir, err)
+			}
+		}
+	}
+
+	// 启动浏览器，chromedp 在第一次 Run 时才会真正创建标签页
+	// 设置了 InitialURL 时先只创建标签页，等证书、拦截等设置完成后再导航
+	startup := chromedp.Action(chromedp.Navigate("about:blank"))
+	if options.SkipInitialNavigate || options.InitialURL != "" {
+		startup = chromedp.ActionFunc(func(ctx context.Context) error {
+			return nil
+		})
+	}
+	// 执行上下文要在第一次 Run 之前开始记录，EvaluateInFrame 依赖它们
+	frameContexts := trackFrameContexts(ctx)
+	err := chromedp.Run(ctx, startup)
+	if err != nil {
+		cancel()
+		if options.UserDir != "" && isProfileLocked(err) {
+			return nil, fmt.Errorf("%w: %s: %v", ErrProfileLocked, options.UserDir, err)
+		}
+		return nil, err
+	}
+
+	// 获取浏览器实例
+	browser := chromedp.FromContext(ctx)
+	// 忽略证书错误，必须在第一次真正导航之前设置
+	if options.IgnoreCertErrors {
+		if err = chromedp.Run(ctx, security.SetIgnoreCertificateErrors(true)); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	// 设置网络拦截器，模拟响应需要在第一次真正导航之前就绪
+	if options.HookFunc != nil || len(options.ResponseMocks) > 0 {
+		if err = chromedp.Run(ctx, launchFetchEnable(options)); err != nil {
+			log.Println(err)
+			cancel()
+			return nil, err
+		}
+	}
+	// 两者同时设置时只注册一个监听器，由 mockHandler 先匹配，未匹配的事件再转给 HookFunc
+	var hook func(ev interface{})
+	if options.HookFunc != nil {
+		hook = options.HookFunc(ctx)
+	}
+	if len(options.ResponseMocks) > 0 {
+		chromedp.ListenTarget(ctx, mockHandler(ctx, options.ResponseMocks, hook))
+	} else if hook != nil {
+		chromedp.ListenTarget(ctx, hook)
+	}
+	if options.AutoAcceptBeforeUnload {
+		acceptBeforeUnload(ctx)
+	}
+	if options.ConsoleWriter != nil {
+		chromedp.ListenTarget(ctx, newConsoleWriter(options.ConsoleWriter, id).handle)
+	}
+
+	// 注入实例 ID，新文档和当前文档都需要
+	if options.InjectInstanceID {
+		script := fmt.Sprintf(`window.__browserInstanceId = %d;`, id)
+		err = chromedp.Run(ctx,
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				_, err := page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+				return err
+			}),
+			chromedp.Evaluate(script, nil),
+		)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	if options.InitialURL != "" {
+		if err = chromedp.Run(ctx, chromedp.Navigate(options.InitialURL)); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	// 创建 BrowserInstance
+	instance := NewBrowserInstance(id, browser, ctx, func() {
+
+		cancel()
+		// 连接远程浏览器时没有本地进程
+		if p := browser.Browser.Process(); p != nil {
+			p.Kill()
+		}
+	})
+	for k, v := range options.Labels {
+		instance.Labels[k] = v
+	}
+	instance.DefaultTimeout = options.DefaultTimeout
+	instance.downloadDir = options.DownloadDir
+	instance.fetchAtLaunch = options.HookFunc != nil || len(options.ResponseMocks) > 0
+	instance.userAgentRotator = options.UserAgentRotator
+	instance.options = options
+	instance.frameContexts = frameContexts
+	instance.logEvent(EventLaunched, "", nil)
+	instance.trackResponses()
+	instance.watchCrash()
+
+	// 将浏览器实例添加到控制器中
+	bc.mu.Lock()
+	bc.instances[id] = instance
+	if bc.MaxMemoryMB > 0 && !bc.watching {
+		bc.watching = true
+		go bc.watchMemory()
+	}
+	bc.mu.Unlock()
+
+	return instance, nil
+}
+
+// watchMemory 定期检查实例的内存占用，超出 MaxMemoryMB 的空闲实例会被关闭并重启
+// 没有实例或关闭了 MaxMemoryMB 时退出，下次 LaunchBrowser 时重新启动
+func (bc *BrowserController) watchMemory() {
+	for {
+		interval := bc.MemoryCheckInterval
+		if interval <= 0 {
+			interval = defaultMemoryCheckInterval
+		}
+		time.Sleep(interval)
+
+		bc.mu.Lock()
+		if bc.MaxMemoryMB <= 0 || len(bc.instances) == 0 {
+			bc.watching = false
+			bc.mu.Unlock()
+			return
+		}
+		limit := bc.MaxMemoryMB
+		instances := make([]*BrowserInstance, 0, len(bc.instances))
+		for _, instance := range bc.instances {
+			instances = append(instances, instance)
+		}
+		bc.mu.Unlock()
+
+		for _, instance := range instances {
+			bc.recycleIfOverMemory(instance, limit)
+		}
+	}
+}
+
+// recycleIfOverMemory 内存超限且空闲时关闭实例并用原参数重新启动，新实例的 ID 与旧实例不同，通过 OnRecycle 通知调用方
+func (bc *BrowserController) recycleIfOverMemory(instance *BrowserInstance, limitMB int) {
+	// 隔离子实例与父实例共享进程，由父实例的统计决定是否回收；接管的实例没有启动参数，无法重启
+	if instance.Closed() || instance.Busy() || instance.parentID != 0 || instance.adopted {
+		return
+	}
+	stats, err := instance.ResourceUsage()
+	if err != nil || stats.RSSMB() <= limitMB {
+		return
+	}
+	// 池中的实例只在空闲时回收，先从空闲队列取出，避免重启期间被借出
+	pool := instance.pool.Load()
+	if pool != nil && !pool.takeIdle(instance) {
+		return
+	}
+	// 持有操作锁再关闭，避免关闭过程中有新的操作开始
+	release, ok := instance.tryAcquire()
+	if !ok {
+		if pool != nil {
+			pool.putRecycled(instance, nil)
+		}
+		return
+	}
+	log.Printf("Browser instance %d uses %d MB (limit %d MB), relaunching", instance.ID, stats.RSSMB(), limitMB)
+	instance.setCloseReason(CloseReasonMemoryLimit)
+	err = bc.CloseBrowser(instance.ID)
+	release()
+	if err != nil {
+		if pool != nil {
+			pool.putRecycled(nil, err)
+		}
+		return
+	}
+	replacement, err := bc.LaunchBrowser(instance.options)
+	if pool != nil {
+		pool.putRecycled(replacement, err)
+	}
+	if err != nil {
+		log.Printf("Failed to relaunch browser instance %d: %v", instance.ID, err)
+		return
+	}
+	if bc.OnRecycle != nil {
+		bc.OnRecycle(instance, replacement)
+	}
+}
+
+// headlessFlag 根据 HeadlessMode 和 Headless 生成 headless 启动参数
+func headlessFlag(options BrowserOptions) chromedp.ExecAllocatorOption {
+	switch options.HeadlessMode {
+	case HeadlessModeOff:
+		return chromedp.Flag("headless", false)
+	case HeadlessModeLegacy:
+		return chromedp.Flag("headless", true)
+	case HeadlessModeNew:
+		return chromedp.Flag("headless", "new")
+	}
+	return chromedp.Flag("headless", options.Headless)
+}
+
+// waitLaunchSlot 按 LaunchRateLimit 预约一个启动时间并等待到达
+func (bc *BrowserController) waitLaunchSlot() {
+	bc.rateMu.Lock()
+	if bc.LaunchRateLimit <= 0 {
+		bc.rateMu.Unlock()
+		return
+	}
+	now := time.Now()
+	slot := bc.nextLaunch
+	if slot.Before(now) {
+		slot = now
+	}
+	bc.nextLaunch = slot.Add(bc.LaunchRateLimit)
+	bc.rateMu.Unlock()
 
+	time.Sleep(time.Until(slot))
+}
+
+// execAllocatorOptions 根据 options 生成本地启动浏览器的参数
+// 设置了 CloneUserDir 时返回复制出的临时目录，由调用方在浏览器退出后删除
+func execAllocatorOptions(options BrowserOptions) ([]chromedp.ExecAllocatorOption, string, error) {
 	// 配置浏览器启动参数
+	// 设置了 BaseAllocatorOptions 时用它代替 chromedp 的默认参数
+	base := chromedp.DefaultExecAllocatorOptions[:]
+	if options.BaseAllocatorOptions != nil {
+		base = options.BaseAllocatorOptions
+	}
 	allocatorOpts := append(
-		chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.ExecPath(options.Path),             // 指定浏览器路径
-		chromedp.Flag("headless", options.Headless), // 是否启用无头模式
+		base[:len(base):len(base)],
+		chromedp.ExecPath(options.Path), // 指定浏览器路径
+		headlessFlag(options),           // 是否启用无头模式
 	)
 	for _, flag := range options.Flags {
 		allocatorOpts = append(allocatorOpts, flag)
 	}
 
-	if options.UserDir != "" {
+	// 复制用户目录，避免多个实例共享同一个目录时的锁冲突
+	var clonedDir string
+	if options.UserDir != "" && options.CloneUserDir {
+		dir, err := cloneUserDir(options.UserDir)
+		if err != nil {
+			return nil, "", fmt.Errorf("clone user dir %s: %w", options.UserDir, err)
+		}
+		clonedDir = dir
+		allocatorOpts = append(allocatorOpts, chromedp.UserDataDir(clonedDir))
+	} else if options.UserDir != "" {
 		allocatorOpts = append(allocatorOpts, chromedp.UserDataDir(options.UserDir))
 	}
 
@@ -75,41 +463,88 @@ func (bc *BrowserController) LaunchBrowser(options BrowserOptions) (*BrowserInst
 		allocatorOpts = append(allocatorOpts, chromedp.Flag("fp", options.Fingerprint))
 	}
 
-	// 创建上下文
-	ctx, cancel := chromedp.NewExecAllocator(context.Background(), allocatorOpts...)
-	ctx, cancel = chromedp.NewContext(ctx)
+	if len(options.Env) > 0 {
+		allocatorOpts = append(allocatorOpts, chromedp.Env(options.Env...))
+	}
 
-	// 启动浏览器
-	err := chromedp.Run(ctx, chromedp.Navigate("about:blank"))
-	if err != nil {
-		cancel()
-		return nil, err
+	if options.DisableImages {
+		allocatorOpts = append(allocatorOpts, chromedp.Flag("blink-settings", "imagesEnabled=false"))
 	}
 
-	// 获取浏览器实例
-	browser := chromedp.FromContext(ctx)
-	// 设置网络拦截器
-	if options.HookFunc != nil {
-		if err = chromedp.Run(ctx, fetch.Enable()); err != nil {
-			log.Println(err)
-			return nil, err
-		}
-		chromedp.ListenTarget(ctx, options.HookFunc(ctx))
+	// chromedp 只保留最后一个 ModifyCmdFunc，这里把 DetachOnExit 和 ModifyCmd 合并成一个
+	if options.DetachOnExit || options.ModifyCmd != nil {
+		allocatorOpts = append(allocatorOpts, chromedp.ModifyCmdFunc(func(cmd *exec.Cmd) {
+			if options.DetachOnExit {
+				detachCmd(cmd)
+			} else {
+				killWithParent(cmd)
+			}
+			if options.ModifyCmd != nil {
+				options.ModifyCmd(cmd)
+			}
+		}))
 	}
 
-	// 创建 BrowserInstance
-	id := bc.nextID
-	bc.nextID++
-	instance := NewBrowserInstance(id, browser, ctx, func() {
+	return allocatorOpts, clonedDir, nil
+}
 
-		cancel()
-		browser.Browser.Process().Kill()
-	})
+// warnRemoteIgnored 提示连接远程浏览器时不生效的本地启动参数
+func warnRemoteIgnored(options BrowserOptions) {
+	var ignored []string
+	if options.Path != "" {
+		ignored = append(ignored, "Path")
+	}
+	if options.Headless || options.HeadlessMode != HeadlessModeDefault {
+		ignored = append(ignored, "Headless")
+	}
+	if len(options.Flags) > 0 {
+		ignored = append(ignored, "Flags")
+	}
+	if options.UserDir != "" {
+		ignored = append(ignored, "UserDir")
+	}
+	if options.Proxy != "" {
+		ignored = append(ignored, "Proxy")
+	}
+	if options.Fingerprint != "" {
+		ignored = append(ignored, "Fingerprint")
+	}
+	if options.WindowSize != nil || options.DisableGPU {
+		ignored = append(ignored, "WindowSize/DisableGPU")
+	}
+	if len(options.Env) > 0 {
+		ignored = append(ignored, "Env")
+	}
+	if options.DisableImages {
+		ignored = append(ignored, "DisableImages")
+	}
+	if options.DetachOnExit || options.ModifyCmd != nil {
+		ignored = append(ignored, "DetachOnExit/ModifyCmd")
+	}
+	if len(ignored) > 0 {
+		log.Printf("RemoteURL is set, ignoring local launch options: %s", strings.Join(ignored, ", "))
+	}
+}
 
-	// 将浏览器实例添加到控制器中
-	bc.instances[id] = instance
+// validateEnv 检查环境变量是否均为 KEY=value 格式
+func validateEnv(env []string) error {
+	for _, kv := range env {
+		if i := strings.IndexByte(kv, '='); i <= 0 {
+			return fmt.Errorf("环境变量格式错误，应为 KEY=value: %q", kv)
+		}
+	}
+	return nil
+}
 
-	return instance, nil
+// isProfileLocked 根据 Chrome 的启动错误文本判断是否为用户目录被占用
+func isProfileLocked(err error) bool {
+	msg := err.Error()
+	for _, marker := range profileLockedMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
 }
 
 // CloseBrowser 关闭指定的浏览器实例
@@ -119,13 +554,93 @@ func (bc *BrowserController) CloseBrowser(id int) error {
 
 	instance, exists := bc.instances[id]
 	if !exists {
-		return fmt.Errorf("browser instance with ID %d does not exist", id)
+		return fmt.Errorf("%w: browser instance with ID %d", ErrInstanceNotFound, id)
+	}
+
+	bc.closeLocked(id, instance)
+	return nil
+}
+
+// closeLocked 关闭实例及其隔离子实例并从映射中移除，调用时需持有 bc.mu
+func (bc *BrowserController) closeLocked(id int, instance *BrowserInstance) {
+	// 先关闭子实例，在 Chrome 进程退出前销毁它们的浏览器上下文
+	for _, childID := range bc.children[id] {
+		if child, ok := bc.instances[childID]; ok {
+			child.setCloseReason(CloseReasonParentClosed)
+			bc.closeLocked(childID, child)
+		}
 	}
+	delete(bc.children, id)
 
 	instance.Close()
 
 	delete(bc.instances, id) // 从映射中移除
-	return nil
+}
+
+// LaunchIsolated 在 parentID 实例的 Chrome 进程中创建一个隔离的浏览器上下文，作为新实例管理
+// 与 LaunchBrowser 相比不会启动新的进程，适合大量需要独立 cookie 的会话；关闭父实例时会一并关闭
+func (bc *BrowserController) LaunchIsolated(parentID int) (*BrowserInstance, error) {
+	bc.mu.Lock()
+	parent, exists := bc.instances[parentID]
+	if !exists {
+		bc.mu.Unlock()
+		return nil, fmt.Errorf("%w: browser instance with ID %d", ErrInstanceNotFound, parentID)
+	}
+	if bc.draining {
+		bc.mu.Unlock()
+		return nil, ErrDraining
+	}
+	id := bc.nextID
+	bc.nextID++
+	bc.mu.Unlock()
+
+	instance, err := parent.newIsolated(id)
+	if err != nil {
+		return nil, err
+	}
+	instance.parentID = parentID
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	// 创建期间父实例可能已被关闭
+	if _, exists = bc.instances[parentID]; !exists {
+		instance.Close()
+		return nil, fmt.Errorf("%w: browser instance with ID %d", ErrInstanceNotFound, parentID)
+	}
+	bc.instances[id] = instance
+	bc.children[parentID] = append(bc.children[parentID], id)
+	return instance, nil
+}
+
+// Adopt 将调用方自行创建的 chromedp 上下文交给控制器管理，分配 ID 并在上下文结束时自动关闭
+// ctx 必须由 chromedp.NewContext 创建；cancel 在实例关闭时调用，负责释放上下文和浏览器
+func (bc *BrowserController) Adopt(ctx context.Context, cancel context.CancelFunc) (*BrowserInstance, error) {
+	c := chromedp.FromContext(ctx)
+	if c == nil {
+		return nil, fmt.Errorf("context is not created by chromedp.NewContext")
+	}
+	// 尚未运行过的上下文在这里分配浏览器和标签页
+	if err := chromedp.Run(ctx); err != nil {
+		return nil, err
+	}
+
+	bc.mu.Lock()
+	if bc.draining {
+		bc.mu.Unlock()
+		return nil, ErrDraining
+	}
+	id := bc.nextID
+	bc.nextID++
+
+	instance := NewBrowserInstance(id, c, ctx, cancel)
+	instance.adopted = true
+	instance.logEvent(EventLaunched, "adopted", nil)
+	bc.instances[id] = instance
+	bc.mu.Unlock()
+
+	instance.trackResponses()
+	instance.watchCrash()
+	return instance, nil
 }
 
 // GetBrowserInstance 获取指定的浏览器实例
@@ -135,20 +650,103 @@ func (bc *BrowserController) GetBrowserInstance(id int) (*BrowserInstance, error
 
 	instance, exists := bc.instances[id]
 	if !exists {
-		return nil, fmt.Errorf("browser instance with ID %d does not exist", id)
+		return nil, fmt.Errorf("%w: browser instance with ID %d", ErrInstanceNotFound, id)
 	}
 
 	return instance, nil
 }
 
+// CloseByLabel 关闭所有标签 key 的值等于 value 的实例，返回关闭的数量
+func (bc *BrowserController) CloseByLabel(key, value string) int {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	count := 0
+	for id, instance := range bc.instances {
+		if v, ok := instance.Labels[key]; !ok || v != value {
+			continue
+		}
+		bc.closeLocked(id, instance)
+		count++
+	}
+	return count
+}
+
 // CloseAllBrowsers 关闭所有浏览器实例
 func (bc *BrowserController) CloseAllBrowsers() {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
 	for id, instance := range bc.instances {
-		instance.Close()
-		delete(bc.instances, id)
+		bc.closeLocked(id, instance)
+	}
+}
+
+// PauseAll 禁用所有未关闭实例的页面脚本执行，用于冻结整个浏览器集群
+// 失败的实例不会被标记为暂停，错误合并后返回
+func (bc *BrowserController) PauseAll() error {
+	return bc.setAllPaused(true)
+}
+
+// ResumeAll 恢复 PauseAll 暂停的实例，未被暂停的实例不受影响
+func (bc *BrowserController) ResumeAll() error {
+	return bc.setAllPaused(false)
+}
+
+func (bc *BrowserController) setAllPaused(paused bool) error {
+	bc.mu.Lock()
+	instances := make([]*BrowserInstance, 0, len(bc.instances))
+	for _, instance := range bc.instances {
+		if !instance.Closed() {
+			instances = append(instances, instance)
+		}
+	}
+	bc.mu.Unlock()
+
+	var errs []error
+	for _, instance := range instances {
+		if err := instance.setScriptsPaused(paused); err != nil {
+			errs = append(errs, fmt.Errorf("browser instance %d: %w", instance.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Drain 使控制器进入排空状态，之后的 LaunchBrowser 和 LaunchIsolated 返回 ErrDraining，
+// 已有实例不受影响，直到被关闭
+func (bc *BrowserController) Drain() {
+	bc.mu.Lock()
+	bc.draining = true
+	bc.mu.Unlock()
+}
+
+// Shutdown 调用 Drain 并等待所有实例关闭
+// ctx 结束时关闭剩余的实例并返回 ctx.Err()
+func (bc *BrowserController) Shutdown(ctx context.Context) error {
+	bc.Drain()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		bc.mu.Lock()
+		for id, instance := range bc.instances {
+			// 因崩溃等原因自行关闭的实例不会从映射中移除，在这里清理
+			if instance.Closed() {
+				bc.closeLocked(id, instance)
+			}
+		}
+		remaining := len(bc.instances)
+		bc.mu.Unlock()
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			bc.CloseAllBrowsers()
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
 }
 
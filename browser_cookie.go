@@ -0,0 +1,302 @@
+package browsers
+
+import (
+	"context"
+	"fmt"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/luoxk/chromedp"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cookiePollInterval OnCookieChange 检查 cookie 变化的间隔
+const cookiePollInterval = 500 * time.Millisecond
+
+// Cookie 在 http.Cookie 的基础上附带分区键，http.Cookie 只能表示是否分区，无法表示分区所属的顶级站点
+type Cookie struct {
+	*http.Cookie
+	PartitionKey string // 分区 cookie（CHIPS）的顶级站点，如 "https://example.com"，为空表示不分区
+}
+
+// SetCookies 将 cookies 一次性写入浏览器，每个 cookie 都必须设置 Domain
+// chromedp 附加页面时已启用 Network 域，新启动、尚未导航的实例也可以调用
+// 设置了 PartitionKey 的 cookie 按分区 cookie 写入，浏览器要求它们是 Secure 的，未设置时返回错误；
+// Partitioned 为 true 但没有 PartitionKey 时无法确定分区所属的顶级站点，同样返回错误
+func (bi *BrowserInstance) SetCookies(cookies []*Cookie) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for i, c := range cookies {
+		if c == nil || c.Cookie == nil {
+			return fmt.Errorf("第 %d 个 cookie 为 nil", i)
+		}
+		if c.Partitioned && c.PartitionKey == "" {
+			return fmt.Errorf("分区 cookie %s 没有设置 PartitionKey", c.Name)
+		}
+		if c.Domain == "" {
+			return fmt.Errorf("cookie %s 没有设置 Domain", c.Name)
+		}
+		p := toCookieParam(c.Cookie)
+		if c.PartitionKey != "" {
+			if !c.Secure {
+				return fmt.Errorf("分区 cookie %s 必须设置 Secure", c.Name)
+			}
+			p.PartitionKey = &network.CookiePartitionKey{TopLevelSite: c.PartitionKey}
+		}
+		params = append(params, p)
+	}
+
+	return chromedp.Run(bi.Ctx, network.SetCookies(params))
+}
+
+// RawCookies 返回当前页面可见的 CDP 原始 cookie，包含 http.Cookie 无法表示的分区键等字段
+func (bi *BrowserInstance) RawCookies() ([]*network.Cookie, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	var cookies []*network.Cookie
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("获取 cookies 失败: %w", err)
+	}
+	return cookies, nil
+}
+
+// SetCookiesForDomain 将 cookies 的域名统一替换为 domain 后一次性写入浏览器
+// 原 cookie 是域 cookie（以 . 开头）时保留前导点，使其继续对子域生效
+func (bi *BrowserInstance) SetCookiesForDomain(cookies []*http.Cookie, domain string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	host, err := normalizeCookieDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, c := range cookies {
+		p := toCookieParam(c)
+		p.Domain = host
+		if strings.HasPrefix(c.Domain, ".") || strings.HasPrefix(domain, ".") {
+			p.Domain = "." + host
+		}
+		params = append(params, p)
+	}
+
+	return chromedp.Run(bi.Ctx, network.SetCookies(params))
+}
+
+// CookieHeader 返回访问 url 时浏览器会携带的 Cookie 请求头，例如 "a=1; b=2"
+// 域名、路径和 Secure 的匹配由浏览器完成，顺序按 RFC 6265 路径长的在前
+func (bi *BrowserInstance) CookieHeader(url string) (string, error) {
+	if bi.Closed() {
+		return "", ErrBrowserClosed
+	}
+
+	var cookies []*network.Cookie
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().WithURLs([]string{url}).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return "", fmt.Errorf("获取 cookies 失败: %w", err)
+	}
+
+	sort.SliceStable(cookies, func(i, j int) bool {
+		return len(cookies[i].Path) > len(cookies[j].Path)
+	})
+	pairs := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		pairs = append(pairs, c.Name+"="+c.Value)
+	}
+	return strings.Join(pairs, "; "), nil
+}
+
+// GetCookiesForURLs 一次请求取出 urls 涉及的所有 cookie，按 URL 分组返回
+// 每个 URL 只包含浏览器访问它时会携带的 cookie（域名、路径和 Secure 均匹配）
+func (bi *BrowserInstance) GetCookiesForURLs(urls []string) (map[string][]*http.Cookie, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	parsed := make([]*url.URL, len(urls))
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("URL 不合法: %s", raw)
+		}
+		parsed[i] = u
+	}
+
+	var cookies []*network.Cookie
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().WithURLs(urls).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("获取 cookies 失败: %w", err)
+	}
+
+	result := make(map[string][]*http.Cookie, len(urls))
+	for i, raw := range urls {
+		var matched []*network.Cookie
+		for _, c := range cookies {
+			if cookieMatchesURL(c, parsed[i]) {
+				matched = append(matched, c)
+			}
+		}
+		result[raw] = toHTTPCookies(convertCookies(matched))
+	}
+	return result, nil
+}
+
+// cookieMatchesURL 按 RFC 6265 判断访问 u 时是否会携带 c
+func cookieMatchesURL(c *network.Cookie, u *url.URL) bool {
+	if c.Secure && u.Scheme != "https" && u.Scheme != "wss" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	domain := strings.ToLower(c.Domain)
+	if strings.HasPrefix(domain, ".") {
+		// 域 cookie 对该域名及其子域生效
+		domain = domain[1:]
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	} else if host != domain {
+		return false
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	cp := c.Path
+	if cp == "" {
+		cp = "/"
+	}
+	if path == cp {
+		return true
+	}
+	return strings.HasPrefix(path, cp) && (strings.HasSuffix(cp, "/") || path[len(cp)] == '/')
+}
+
+// OnCookieChange 在浏览器的 cookie 发生增删改时调用 handler，参数为变化后的全部 cookie
+// CDP 没有 cookie 变化事件，这里每隔 cookiePollInterval 对比一次，实例关闭后自动停止
+func (bi *BrowserInstance) OnCookieChange(handler func(cookies []*network.Cookie)) {
+	if bi.Closed() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(cookiePollInterval)
+		defer ticker.Stop()
+		var last map[string]string
+		for {
+			select {
+			case <-bi.Ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var cookies []*network.Cookie
+			err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+				// storage.getCookies 返回整个浏览器上下文的 cookie，不限于当前页面
+				c := chromedp.FromContext(ctx)
+				p := storage.GetCookies()
+				if c.BrowserContextID != "" {
+					p = p.WithBrowserContextID(c.BrowserContextID)
+				}
+				var err error
+				cookies, err = p.Do(cdp.WithExecutor(ctx, c.Browser))
+				return err
+			}))
+			if err != nil {
+				continue
+			}
+
+			cur := cookieSet(cookies)
+			// 第一次只记录基准，不触发
+			if last != nil && !sameCookieSet(last, cur) {
+				handler(cookies)
+			}
+			last = cur
+		}
+	}()
+}
+
+// cookieSet 以 name、domain、path 为键记录 cookie 的值
+func cookieSet(cookies []*network.Cookie) map[string]string {
+	set := make(map[string]string, len(cookies))
+	for _, c := range cookies {
+		set[c.Name+"\x00"+c.Domain+"\x00"+c.Path] = c.Value
+	}
+	return set
+}
+
+func sameCookieSet(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeCookieDomain 去除前导点并校验域名，拒绝带协议、路径或端口的写法
+func normalizeCookieDomain(domain string) (string, error) {
+	host := strings.TrimPrefix(strings.TrimSpace(domain), ".")
+	if host == "" {
+		return "", fmt.Errorf("cookie 域名为空")
+	}
+	if strings.ContainsAny(host, "/:@ \t") || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+		return "", fmt.Errorf("cookie 域名不合法: %s", domain)
+	}
+	return strings.ToLower(host), nil
+}
+
+// toCookieParam 将 http.Cookie 转换为 CDP 的 CookieParam
+func toCookieParam(c *http.Cookie) *network.CookieParam {
+	p := &network.CookieParam{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HTTPOnly: c.HttpOnly,
+	}
+	if p.Path == "" {
+		p.Path = "/"
+	}
+	if !c.Expires.IsZero() {
+		expires := cdp.TimeSinceEpoch(c.Expires)
+		p.Expires = &expires
+	}
+	switch c.SameSite {
+	case http.SameSiteStrictMode:
+		p.SameSite = network.CookieSameSiteStrict
+	case http.SameSiteLaxMode:
+		p.SameSite = network.CookieSameSiteLax
+	case http.SameSiteNoneMode:
+		p.SameSite = network.CookieSameSiteNone
+	}
+	return p
+}
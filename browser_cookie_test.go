@@ -0,0 +1,115 @@
+package browsers
+
+import (
+	"context"
+	"errors"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestNormalizeCookieDomain(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "example.com", want: "example.com"},
+		{in: ".Example.com", want: "example.com"},
+		{in: " staging.example.com ", want: "staging.example.com"},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "https://example.com", wantErr: true},
+		{in: "example.com:8080", wantErr: true},
+		{in: "example.com/path", wantErr: true},
+		{in: "..example.com", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := normalizeCookieDomain(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("normalizeCookieDomain(%q) 应返回错误，实际得到 %q", c.in, got)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("normalizeCookieDomain(%q) = %q, %v; 期望 %q", c.in, got, err, c.want)
+		}
+	}
+}
+
+func TestCookieMatchesURL(t *testing.T) {
+	cases := []struct {
+		cookie network.Cookie
+		url    string
+		want   bool
+	}{
+		{cookie: network.Cookie{Domain: "example.com", Path: "/"}, url: "https://example.com/a", want: true},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/"}, url: "https://www.example.com/", want: false},
+		{cookie: network.Cookie{Domain: ".example.com", Path: "/"}, url: "https://www.example.com/", want: true},
+		{cookie: network.Cookie{Domain: ".example.com", Path: "/"}, url: "https://badexample.com/", want: false},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/api"}, url: "https://example.com/api/v1", want: true},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/api"}, url: "https://example.com/apix", want: false},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/api/"}, url: "https://example.com/api/v1", want: true},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/", Secure: true}, url: "http://example.com/", want: false},
+		{cookie: network.Cookie{Domain: "example.com", Path: "/"}, url: "https://example.com", want: true},
+	}
+	for _, c := range cases {
+		u, err := url.Parse(c.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cookieMatchesURL(&c.cookie, u); got != c.want {
+			t.Errorf("cookieMatchesURL(%+v, %s) = %v; 期望 %v", c.cookie, c.url, got, c.want)
+		}
+	}
+}
+
+func TestConvertCookies_PartitionKey(t *testing.T) {
+	cookies := convertCookies([]*network.Cookie{
+		{Name: "a", Domain: "embed.example", Secure: true, PartitionKey: &network.CookiePartitionKey{TopLevelSite: "https://example.com"}},
+		{Name: "b", Domain: "embed.example"},
+	})
+	if !cookies[0].Partitioned || cookies[0].PartitionKey != "https://example.com" {
+		t.Errorf("分区 cookie 转换后 Partitioned=%v PartitionKey=%q", cookies[0].Partitioned, cookies[0].PartitionKey)
+	}
+	if cookies[1].Partitioned || cookies[1].PartitionKey != "" {
+		t.Errorf("普通 cookie 不应带分区键: %q", cookies[1].PartitionKey)
+	}
+}
+
+func TestCookieErrorsWrapCause(t *testing.T) {
+	// 不是 chromedp 创建的上下文，chromedp.Run 返回 ErrInvalidContext
+	bi := NewBrowserInstanceNoMonitor(1, nil, context.Background(), nil)
+
+	_, err := bi.RawCookies()
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("RawCookies 应保留原始错误，实际为 %v", err)
+	}
+	_, err = bi.CookieHeader("https://example.com/")
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("CookieHeader 应保留原始错误，实际为 %v", err)
+	}
+	_, err = bi.GetCookiesForURLs([]string{"https://example.com/"})
+	if !errors.Is(err, chromedp.ErrInvalidContext) {
+		t.Errorf("GetCookiesForURLs 应保留原始错误，实际为 %v", err)
+	}
+}
+
+func TestSetCookies_RejectsInvalid(t *testing.T) {
+	bi := NewBrowserInstanceNoMonitor(1, nil, context.Background(), nil)
+
+	cases := map[string][]*Cookie{
+		"nil":     {nil},
+		"nil 内嵌":  {{PartitionKey: "https://example.com"}},
+		"分区缺少分区键": {{Cookie: &http.Cookie{Name: "a", Domain: "example.com", Secure: true, Partitioned: true}}},
+	}
+	for name, cookies := range cases {
+		err := bi.SetCookies(cookies)
+		if err == nil || errors.Is(err, chromedp.ErrInvalidContext) {
+			t.Errorf("%s: SetCookies 应在写入浏览器之前返回错误，实际为 %v", name, err)
+		}
+	}
+}
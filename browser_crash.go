@@ -0,0 +1,42 @@
+package browsers
+
+import (
+	"github.com/chromedp/cdproto/inspector"
+	"github.com/luoxk/chromedp"
+	"log"
+)
+
+// OnCrash 注册渲染进程崩溃时调用的 handler，与正常关闭区分开，便于立即重启
+// 所有 handler 在同一个专用 goroutine 中按注册顺序依次调用，此时实例的关闭原因已记为 CloseReasonCrashed，
+// 全部返回后实例被关闭；handler 中仍可读取实例的标签、事件等状态，但不应再对它执行操作
+func (bi *BrowserInstance) OnCrash(handler func()) {
+	bi.crashMu.Lock()
+	bi.crashHandlers = append(bi.crashHandlers, handler)
+	bi.crashMu.Unlock()
+}
+
+// watchCrash 启用 Inspector 域并监听目标崩溃事件，启动实例时调用
+func (bi *BrowserInstance) watchCrash() {
+	chromedp.ListenTarget(bi.Ctx, func(ev interface{}) {
+		if _, ok := ev.(*inspector.EventTargetCrashed); !ok {
+			return
+		}
+		// 先记录原因，之后上下文结束触发的 monitorContext 不会覆盖
+		bi.setCloseReason(CloseReasonCrashed)
+		bi.logEvent(EventErrored, "target crashed", nil)
+
+		bi.crashMu.Lock()
+		handlers := append([]func(){}, bi.crashHandlers...)
+		bi.crashMu.Unlock()
+		// 事件监听运行在 chromedp 的事件循环中，不能在这里阻塞
+		go func() {
+			for _, h := range handlers {
+				h()
+			}
+			bi.Close()
+		}()
+	})
+	if err := chromedp.Run(bi.Ctx, inspector.Enable()); err != nil {
+		log.Printf("Failed to enable inspector for browser instance %d: %v", bi.ID, err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package browsers
+
+import "os/exec"
+
+// detachCmd 其他系统上 chromedp 默认不会在 Go 进程退出时杀死 Chrome，无需处理
+func detachCmd(cmd *exec.Cmd) {}
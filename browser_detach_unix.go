@@ -0,0 +1,17 @@
+//go:build unix
+
+package browsers
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachCmd 让 Chrome 在独立的进程组中运行，且不随 Go 进程退出而被杀死
+// 代替 chromedp 默认设置的 Pdeathsig，终端的 Ctrl+C 也不会再传给 Chrome
+func detachCmd(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
@@ -0,0 +1,25 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/page"
+	"github.com/luoxk/chromedp"
+	"log"
+)
+
+// acceptBeforeUnload 自动确认 beforeunload 对话框，其他类型的对话框不处理
+// 网页通过 beforeunload 拦截离开时，导航或关闭会被对话框阻塞
+func acceptBeforeUnload(ctx context.Context) {
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		e, ok := ev.(*page.EventJavascriptDialogOpening)
+		if !ok || e.Type != page.DialogTypeBeforeunload {
+			return
+		}
+		// 监听回调中不能同步执行命令，否则会阻塞事件分发
+		go func() {
+			if err := chromedp.Run(ctx, page.HandleJavaScriptDialog(true)); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to accept beforeunload dialog on %s: %v", e.URL, err)
+			}
+		}()
+	})
+}
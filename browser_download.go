@@ -0,0 +1,155 @@
+package browsers
+
+import (
+	"context"
+	"fmt"
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/luoxk/chromedp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// WaitForDownload 等待下一个下载完成，返回文件路径
+func (bi *BrowserInstance) WaitForDownload(timeout time.Duration) (string, error) {
+	files, err := bi.WaitForDownloads(1, timeout)
+	if err != nil {
+		return "", err
+	}
+	return files[0], nil
+}
+
+// WaitForDownloads 等待 n 个下载完成，返回文件路径
+// 超时时返回已完成的文件以及 ErrWaitTimeout；被取消的下载不计入 n
+func (bi *BrowserInstance) WaitForDownloads(n int, timeout time.Duration) ([]string, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	dir, err := bi.ensureDownloadDir()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout)
+	defer cancel()
+
+	// 下载事件是浏览器级的，同一浏览器中其他标签页的下载也会收到，只统计本标签页各 frame 发起的下载
+	frames, err := bi.downloadFrames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		names = make(map[string]string) // GUID -> 建议文件名
+		files []string
+		done  = make(chan struct{})
+	)
+	chromedp.ListenBrowser(ctx, func(ev interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch ev := ev.(type) {
+		case *browser.EventDownloadWillBegin:
+			if frames[ev.FrameID] {
+				names[ev.GUID] = ev.SuggestedFilename
+			}
+		case *browser.EventDownloadProgress:
+			if _, ok := names[ev.GUID]; !ok {
+				return
+			}
+			if ev.State != browser.DownloadProgressStateCompleted || len(files) >= n {
+				return
+			}
+			files = append(files, finalizeDownload(dir, ev.GUID, names[ev.GUID]))
+			if len(files) == n {
+				close(done)
+			}
+		}
+	})
+
+	// 下载行为是浏览器级设置，需要使用 Browser 执行器；实例有自己的浏览器上下文时只对该上下文生效
+	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c := chromedp.FromContext(ctx)
+		set := browser.SetDownloadBehavior(browser.SetDownloadBehaviorBehaviorAllowAndName).
+			WithDownloadPath(dir).
+			WithEventsEnabled(true)
+		if c.BrowserContextID != "" {
+			set = set.WithBrowserContextID(c.BrowserContextID)
+		}
+		return set.Do(cdp.WithExecutor(ctx, c.Browser))
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := append([]string(nil), files...)
+	if len(result) < n {
+		if bi.Ctx.Err() != nil {
+			return result, bi.Ctx.Err()
+		}
+		return result, fmt.Errorf("%w: 已完成 %d/%d 个下载", ErrWaitTimeout, len(result), n)
+	}
+	return result, nil
+}
+
+// downloadFrames 返回本标签页当前所有 frame 的 ID，等待期间新建的 iframe 发起的下载不会被统计
+func (bi *BrowserInstance) downloadFrames(ctx context.Context) (map[cdp.FrameID]bool, error) {
+	frames := make(map[cdp.FrameID]bool)
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, frame := range flattenFrameTree(tree, nil) {
+			frames[cdp.FrameID(frame.ID)] = true
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// ensureDownloadDir 返回下载目录，未设置 DownloadDir 时创建一个临时目录
+func (bi *BrowserInstance) ensureDownloadDir() (string, error) {
+	bi.downloadMu.Lock()
+	defer bi.downloadMu.Unlock()
+	if bi.downloadDir != "" {
+		return bi.downloadDir, os.MkdirAll(bi.downloadDir, 0o755)
+	}
+	dir, err := os.MkdirTemp("", "browsers-download-*")
+	if err != nil {
+		return "", err
+	}
+	bi.downloadDir = dir
+	return dir, nil
+}
+
+// finalizeDownload 将以 GUID 命名的下载文件重命名为建议的文件名
+// 目标文件已存在或重命名失败时保留 GUID 文件名
+func finalizeDownload(dir, guid, suggested string) string {
+	src := filepath.Join(dir, guid)
+	if suggested == "" {
+		return src
+	}
+	dst := filepath.Join(dir, filepath.Base(suggested))
+	if _, err := os.Stat(dst); err == nil {
+		return src
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return src
+	}
+	return dst
+}
@@ -0,0 +1,253 @@
+package browsers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/chromedp/cdproto/input"
+	"github.com/luoxk/chromedp"
+)
+
+// Focus 聚焦选择器匹配到的第一个元素
+func (bi *BrowserInstance) Focus(sel string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx,
+		requireElement(sel),
+		chromedp.Focus(sel, chromedp.ByQuery),
+	)
+}
+
+// Blur 使选择器匹配到的第一个元素失去焦点
+func (bi *BrowserInstance) Blur(sel string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx,
+		requireElement(sel),
+		chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s).blur()`, jsString(sel)), nil),
+	)
+}
+
+// Count 返回选择器匹配到的元素数量，不会等待元素出现
+func (bi *BrowserInstance) Count(sel string) (int, error) {
+	n, err := bi.CallJsInt(fmt.Sprintf(`document.querySelectorAll(%s).length`, jsString(sel)))
+	return int(n), err
+}
+
+// Exists 判断是否存在匹配选择器的元素，不会等待元素出现
+func (bi *BrowserInstance) Exists(sel string) (bool, error) {
+	n, err := bi.Count(sel)
+	return n > 0, err
+}
+
+// requireElement 检查选择器是否存在匹配元素，chromedp 的查询在无匹配时会一直等待
+func requireElement(sel string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var exists bool
+		err := chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null`, jsString(sel)), &exists).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+		}
+		return nil
+	})
+}
+
+// SelectOption 按 value 选中 <select> 的选项，并派发 input/change 事件以触发框架的监听
+func (bi *BrowserInstance) SelectOption(sel, value string) error {
+	return bi.selectOption(sel, `o.value === target`, value)
+}
+
+// SelectOptionByText 按显示文本选中 <select> 的选项
+func (bi *BrowserInstance) SelectOptionByText(sel, text string) error {
+	return bi.selectOption(sel, `o.text.trim() === target.trim()`, text)
+}
+
+func (bi *BrowserInstance) selectOption(sel, match, target string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	var result string
+	err := chromedp.Run(bi.Ctx,
+		chromedp.Evaluate(fmt.Sprintf(`(function() {
+			var el = document.querySelector(%s);
+			if (!el) return "no such element";
+			if (el.tagName !== "SELECT") return "not a select";
+			var target = %s;
+			var opt = Array.prototype.find.call(el.options, function(o) { return %s; });
+			if (!opt) return "no such option";
+			opt.selected = true;
+			el.value = opt.value;
+			el.dispatchEvent(new Event("input", {bubbles: true}));
+			el.dispatchEvent(new Event("change", {bubbles: true}));
+			return "";
+		})()`, jsString(sel), jsString(target), match), &result),
+	)
+	if err != nil {
+		return err
+	}
+	switch result {
+	case "":
+		return nil
+	case "no such element":
+		return fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+	case "not a select":
+		return fmt.Errorf("元素不是 select: %s", sel)
+	default:
+		return fmt.Errorf("未找到选项 %q: %s", target, sel)
+	}
+}
+
+// DropFile 模拟将文件拖放到元素上，用于只接受拖放、忽略 <input type=file> 的上传组件
+// 在页面中构造包含该文件的 DataTransfer，依次派发 dragenter、dragover 和 drop 事件
+func (bi *BrowserInstance) DropFile(sel, filename string, content []byte, mimeType string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	var result string
+	err := chromedp.Run(bi.Ctx,
+		chromedp.Evaluate(fmt.Sprintf(`(function() {
+			var el = document.querySelector(%s);
+			if (!el) return "no such element";
+			var bin = atob(%s);
+			var bytes = new Uint8Array(bin.length);
+			for (var i = 0; i < bin.length; i++) bytes[i] = bin.charCodeAt(i);
+			var file = new File([bytes], %s, {type: %s});
+			var dt = new DataTransfer();
+			dt.items.add(file);
+			var rect = el.getBoundingClientRect();
+			var init = {
+				bubbles: true,
+				cancelable: true,
+				dataTransfer: dt,
+				clientX: rect.left + rect.width / 2,
+				clientY: rect.top + rect.height / 2
+			};
+			["dragenter", "dragover", "drop"].forEach(function(type) {
+				el.dispatchEvent(new DragEvent(type, init));
+			});
+			return "";
+		})()`, jsString(sel), jsString(base64.StdEncoding.EncodeToString(content)), jsString(filename), jsString(mimeType)), &result),
+	)
+	if err != nil {
+		return err
+	}
+	if result == "no such element" {
+		return fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+	}
+	return nil
+}
+
+// Hover 将鼠标移动到元素中心，用于触发仅在悬停时出现的菜单和提示
+func (bi *BrowserInstance) Hover(sel string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		x, y, err := elementCenter(ctx, sel)
+		if err != nil {
+			return err
+		}
+		return input.DispatchMouseEvent(input.MouseMoved, x, y).Do(ctx)
+	}))
+}
+
+// elementCenter 将元素滚动到可视区域并返回其中心点的视口坐标
+func elementCenter(ctx context.Context, sel string) (x, y float64, err error) {
+	var box struct {
+		Found  bool    `json:"found"`
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	}
+	err = chromedp.Evaluate(fmt.Sprintf(`(function() {
+		var el = document.querySelector(%s);
+		if (!el) return {"found": false};
+		el.scrollIntoView({block: "center", inline: "center"});
+		var r = el.getBoundingClientRect();
+		return {"found": true, "x": r.left, "y": r.top, "width": r.width, "height": r.height};
+	})()`, jsString(sel)), &box).Do(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !box.Found {
+		return 0, 0, fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+	}
+	if box.Width == 0 || box.Height == 0 {
+		return 0, 0, fmt.Errorf("元素不可见: %s", sel)
+	}
+	return box.X + box.Width/2, box.Y + box.Height/2, nil
+}
+
+// DragAndDrop 从 fromSel 元素中心拖拽到 toSel 元素中心
+// steps 可选，指定中间 mousemove 的次数，部分站点需要多次移动才能识别拖拽
+func (bi *BrowserInstance) DragAndDrop(fromSel, toSel string, steps ...int) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	n := 1
+	if len(steps) > 0 && steps[0] > 1 {
+		n = steps[0]
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		fromX, fromY, err := elementCenter(ctx, fromSel)
+		if err != nil {
+			return err
+		}
+		toX, toY, err := elementCenter(ctx, toSel)
+		if err != nil {
+			return err
+		}
+
+		if err = input.DispatchMouseEvent(input.MouseMoved, fromX, fromY).Do(ctx); err != nil {
+			return err
+		}
+		err = input.DispatchMouseEvent(input.MousePressed, fromX, fromY).
+			WithButton(input.Left).WithButtons(1).WithClickCount(1).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for i := 1; i <= n; i++ {
+			x := fromX + (toX-fromX)*float64(i)/float64(n)
+			y := fromY + (toY-fromY)*float64(i)/float64(n)
+			err = input.DispatchMouseEvent(input.MouseMoved, x, y).
+				WithButton(input.Left).WithButtons(1).Do(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return input.DispatchMouseEvent(input.MouseReleased, toX, toY).
+			WithButton(input.Left).WithButtons(0).WithClickCount(1).Do(ctx)
+	}))
+}
+
+// ComputedStyle 返回元素渲染后的样式值，元素不存在时返回错误，样式为空时返回空字符串
+func (bi *BrowserInstance) ComputedStyle(sel, property string) (string, error) {
+	if bi.Closed() {
+		return "", ErrBrowserClosed
+	}
+
+	var res struct {
+		Found bool   `json:"found"`
+		Value string `json:"value"`
+	}
+	err := chromedp.Run(bi.Ctx, chromedp.Evaluate(fmt.Sprintf(`(function() {
+		var el = document.querySelector(%s);
+		if (!el) return {"found": false};
+		return {"found": true, "value": getComputedStyle(el).getPropertyValue(%s)};
+	})()`, jsString(sel), jsString(property)), &res))
+	if err != nil {
+		return "", err
+	}
+	if !res.Found {
+		return "", fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+	}
+	return res.Value, nil
+}
@@ -0,0 +1,73 @@
+package browsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/luoxk/chromedp"
+)
+
+// SetViewport 覆盖页面的渲染视口，不改变操作系统窗口的大小
+// width、height 为 CSS 像素，deviceScaleFactor 为 0 时使用系统默认值
+func (bi *BrowserInstance) SetViewport(width, height int, deviceScaleFactor float64, mobile bool) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("视口尺寸无效: %dx%d", width, height)
+	}
+	return chromedp.Run(bi.Ctx,
+		emulation.SetDeviceMetricsOverride(int64(width), int64(height), deviceScaleFactor, mobile),
+	)
+}
+
+// ClearEmulationOverrides 清除视口、地理位置、UA、时区和语言的覆盖设置
+// 每项都会尝试清除，失败的项合并后返回
+// 注意设置了 UserAgentRotator 时下一次 Goto 仍会重新覆盖 UA
+func (bi *BrowserInstance) ClearEmulationOverrides() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// 空字符串表示取消 UA 和时区覆盖，不带参数的 SetLocaleOverride 恢复默认语言
+		return errors.Join(
+			emulation.ClearDeviceMetricsOverride().Do(ctx),
+			emulation.ClearGeolocationOverride().Do(ctx),
+			emulation.SetUserAgentOverride("").Do(ctx),
+			emulation.SetTimezoneOverride("").Do(ctx),
+			emulation.SetLocaleOverride().Do(ctx),
+		)
+	}))
+}
+
+// SetZoom 设置页面缩放比例，1 为原始大小
+func (bi *BrowserInstance) SetZoom(factor float64) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	if factor <= 0 {
+		return fmt.Errorf("缩放比例必须为正数: %v", factor)
+	}
+	return chromedp.Run(bi.Ctx, emulation.SetPageScaleFactor(factor))
+}
+
+// Zoom 返回页面当前的缩放比例
+func (bi *BrowserInstance) Zoom() (float64, error) {
+	return bi.CallJsFloat(`window.visualViewport ? window.visualViewport.scale : 1`)
+}
+
+// setScriptsPaused 禁用或恢复页面的脚本执行，已处于目标状态时不发送命令
+func (bi *BrowserInstance) setScriptsPaused(paused bool) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	if bi.paused.Load() == paused {
+		return nil
+	}
+	if err := chromedp.Run(bi.Ctx, emulation.SetScriptExecutionDisabled(paused)); err != nil {
+		return err
+	}
+	bi.paused.Store(paused)
+	return nil
+}
@@ -0,0 +1,15 @@
+package browsers
+
+import "errors"
+
+// 包内方法返回的哨兵错误，可以用 errors.Is 判断，返回时通常会附带上下文信息
+var (
+	// ErrBrowserClosed 表示实例已关闭，无法再执行操作
+	ErrBrowserClosed = errors.New("browser is closed")
+	// ErrInstanceNotFound 表示 BrowserController 中不存在指定 ID 的实例
+	ErrInstanceNotFound = errors.New("browser instance not found")
+	// ErrWaitTimeout 表示等待条件在超时前没有满足
+	ErrWaitTimeout = errors.New("wait timeout")
+	// ErrNoSuchElement 表示选择器没有匹配到元素
+	ErrNoSuchElement = errors.New("no such element")
+)
@@ -0,0 +1,68 @@
+package browsers
+
+import (
+	"sync"
+	"time"
+)
+
+// eventLogSize 每个实例保留的最近事件数量
+const eventLogSize = 100
+
+// EventType 实例生命周期事件的类型
+type EventType string
+
+const (
+	EventLaunched  EventType = "launched"  // 实例启动完成
+	EventNavigated EventType = "navigated" // Goto 导航成功
+	EventErrored   EventType = "errored"   // 操作失败
+	EventClosed    EventType = "closed"    // 实例关闭
+)
+
+// Event 实例的一条生命周期记录
+type Event struct {
+	Time   time.Time
+	Type   EventType
+	Detail string // 导航地址、错误信息或关闭原因
+}
+
+// eventLog 固定容量的环形缓冲区，写满后覆盖最早的记录，零值可用
+type eventLog struct {
+	mu     sync.Mutex
+	events []Event
+	next   int // 下一条记录写入的位置
+}
+
+func (l *eventLog) add(typ EventType, detail string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ev := Event{Time: time.Now(), Type: typ, Detail: detail}
+	if len(l.events) < eventLogSize {
+		l.events = append(l.events, ev)
+		return
+	}
+	l.events[l.next] = ev
+	l.next = (l.next + 1) % eventLogSize
+}
+
+// snapshot 按时间顺序返回记录的副本
+func (l *eventLog) snapshot() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Event, 0, len(l.events))
+	out = append(out, l.events[l.next:]...)
+	return append(out, l.events[:l.next]...)
+}
+
+// EventLog 返回实例最近的生命周期事件，按时间从早到晚排列，最多保留 eventLogSize 条
+func (bi *BrowserInstance) EventLog() []Event {
+	return bi.events.snapshot()
+}
+
+// logEvent 记录一条事件，err 不为 nil 时记为 EventErrored
+func (bi *BrowserInstance) logEvent(typ EventType, detail string, err error) {
+	if err != nil {
+		bi.events.add(EventErrored, detail+": "+err.Error())
+		return
+	}
+	bi.events.add(typ, detail)
+}
@@ -0,0 +1,21 @@
+package browsers
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEventLog_Ring(t *testing.T) {
+	var l eventLog
+	for i := 0; i < eventLogSize+5; i++ {
+		l.add(EventNavigated, strconv.Itoa(i))
+	}
+
+	events := l.snapshot()
+	if len(events) != eventLogSize {
+		t.Fatalf("应只保留 %d 条记录，实际为 %d", eventLogSize, len(events))
+	}
+	if events[0].Detail != "5" || events[len(events)-1].Detail != strconv.Itoa(eventLogSize+4) {
+		t.Fatalf("记录顺序错误: 第一条 %s，最后一条 %s", events[0].Detail, events[len(events)-1].Detail)
+	}
+}
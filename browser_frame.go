@@ -0,0 +1,313 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
+	"github.com/luoxk/chromedp"
+	"sync"
+	"time"
+)
+
+const (
+	isolatedWorldName = "__browsers_isolated" // EvaluateIsolated 创建的隔离环境名称
+	frameContextWait  = 5 * time.Second       // 等待 frame 默认执行上下文出现的最长时间
+)
+
+// Frame 页面中的一个 frame
+type Frame struct {
+	ID       string // frame ID
+	ParentID string // 父 frame ID，顶层 frame 为空
+	URL      string // frame 当前地址
+	Name     string // frame 的 name 属性
+}
+
+// Frames 列出页面中的所有 frame，顶层 frame 排在第一个
+func (bi *BrowserInstance) Frames() ([]Frame, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	var frames []Frame
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		frames = flattenFrameTree(tree, frames)
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+func flattenFrameTree(tree *page.FrameTree, frames []Frame) []Frame {
+	if tree == nil || tree.Frame == nil {
+		return frames
+	}
+	frames = append(frames, Frame{
+		ID:       string(tree.Frame.ID),
+		ParentID: string(tree.Frame.ParentID),
+		URL:      tree.Frame.URL + tree.Frame.URLFragment,
+		Name:     tree.Frame.Name,
+	})
+	for _, child := range tree.ChildFrames {
+		frames = flattenFrameTree(child, frames)
+	}
+	return frames
+}
+
+// EvaluateInFrame 在指定 frame 的默认执行上下文（主世界）中执行 JS，结果写入 out，out 为 nil 时忽略结果
+// 可以访问 frame 中页面脚本定义的全局变量；跨进程 iframe 同样支持
+func (bi *BrowserInstance) EvaluateInFrame(frameID, expr string, out interface{}) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	session, err := bi.frameTarget(frameID)
+	if err != nil {
+		return err
+	}
+	contextID, err := session.contexts.waitMain(session.ctx, cdp.FrameID(frameID))
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(session.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		res, exp, err := runtime.Evaluate(expr).
+			WithContextID(contextID).
+			WithReturnByValue(true).
+			WithAwaitPromise(true).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if exp != nil {
+			return exp
+		}
+		if out == nil || res.Value == nil {
+			return nil
+		}
+		return json.Unmarshal(res.Value, out)
+	}))
+}
+
+// frameSession 可以操作某个 frame 的会话，以及该会话中各 frame 的执行上下文
+type frameSession struct {
+	ctx      context.Context
+	contexts *frameContexts
+}
+
+// frameTarget 返回可以操作 frameID 的会话
+// 同进程的 frame 直接使用页面会话；跨进程 iframe（OOPIF）位于独立的 target 中，target ID 与 frame ID 相同，
+// 需要附加到该 target。附加后的会话缓存到实例关闭为止，不能提前取消，否则 chromedp 会关闭该 target
+func (bi *BrowserInstance) frameTarget(frameID string) (*frameSession, error) {
+	var infos []*target.Info
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		infos, err = target.GetTargets().Do(cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser))
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, info := range infos {
+		if info.Type != "iframe" || string(info.TargetID) != frameID {
+			continue
+		}
+		if v, ok := bi.frameTargets.Load(frameID); ok {
+			return v.(*frameSession), nil
+		}
+		ctx, _ := chromedp.NewContext(bi.Ctx, chromedp.WithTargetID(info.TargetID))
+		// 附加之前注册监听，才能收到 chromedp 启用 Runtime 域时推送的执行上下文
+		session := &frameSession{ctx: ctx, contexts: trackFrameContexts(ctx)}
+		// 不带操作的 Run 只负责附加到 target
+		if err = chromedp.Run(ctx); err != nil {
+			return nil, err
+		}
+		v, _ := bi.frameTargets.LoadOrStore(frameID, session)
+		return v.(*frameSession), nil
+	}
+	// 不是跨进程 iframe，丢弃 frame 已销毁或已回到页面进程时留下的缓存
+	bi.frameTargets.Delete(frameID)
+	contexts, err := bi.pageFrameContexts()
+	if err != nil {
+		return nil, err
+	}
+	return &frameSession{ctx: bi.Ctx, contexts: contexts}, nil
+}
+
+// pageFrameContexts 返回页面会话的执行上下文记录
+// LaunchBrowser 创建的实例在第一次 Run 之前就已注册；Adopt 等途径创建的实例在这里补注册，
+// 并重新启用 Runtime 域，让浏览器重新推送注册之前已存在的执行上下文
+func (bi *BrowserInstance) pageFrameContexts() (*frameContexts, error) {
+	bi.frameContextsMu.Lock()
+	defer bi.frameContextsMu.Unlock()
+	if bi.frameContexts != nil {
+		return bi.frameContexts, nil
+	}
+	contexts := trackFrameContexts(bi.Ctx)
+	if err := chromedp.Run(bi.Ctx, runtime.Disable(), runtime.Enable()); err != nil {
+		return nil, err
+	}
+	bi.frameContexts = contexts
+	return contexts, nil
+}
+
+// frameContexts 根据 Runtime 事件记录一个会话中每个 frame 默认执行上下文和隔离环境的 ID
+type frameContexts struct {
+	mu       sync.Mutex
+	main     map[cdp.FrameID]runtime.ExecutionContextID // frame ID -> 默认执行上下文
+	isolated map[cdp.FrameID]runtime.ExecutionContextID // frame ID -> EvaluateIsolated 创建的隔离环境
+	frames   map[runtime.ExecutionContextID]cdp.FrameID // 执行上下文 -> 所属 frame，用于处理销毁事件
+}
+
+// trackFrameContexts 创建 frameContexts 并监听 ctx 所在 target 的执行上下文事件
+// 需要在 ctx 第一次 Run 之前调用，否则收不到已经存在的执行上下文
+func trackFrameContexts(ctx context.Context) *frameContexts {
+	fc := newFrameContexts()
+	chromedp.ListenTarget(ctx, fc.handle)
+	return fc
+}
+
+func newFrameContexts() *frameContexts {
+	return &frameContexts{
+		main:     make(map[cdp.FrameID]runtime.ExecutionContextID),
+		isolated: make(map[cdp.FrameID]runtime.ExecutionContextID),
+		frames:   make(map[runtime.ExecutionContextID]cdp.FrameID),
+	}
+}
+
+func (fc *frameContexts) handle(ev interface{}) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	switch ev := ev.(type) {
+	case *runtime.EventExecutionContextCreated:
+		var aux struct {
+			FrameID   cdp.FrameID `json:"frameId"`
+			IsDefault bool        `json:"isDefault"`
+		}
+		if err := json.Unmarshal(ev.Context.AuxData, &aux); err != nil || aux.FrameID == "" || !aux.IsDefault {
+			return
+		}
+		fc.main[aux.FrameID] = ev.Context.ID
+		fc.frames[ev.Context.ID] = aux.FrameID
+	case *runtime.EventExecutionContextDestroyed:
+		frameID, ok := fc.frames[ev.ExecutionContextID]
+		if !ok {
+			return
+		}
+		delete(fc.frames, ev.ExecutionContextID)
+		if fc.main[frameID] == ev.ExecutionContextID {
+			delete(fc.main, frameID)
+		}
+		if fc.isolated[frameID] == ev.ExecutionContextID {
+			delete(fc.isolated, frameID)
+		}
+	case *runtime.EventExecutionContextsCleared:
+		fc.main = make(map[cdp.FrameID]runtime.ExecutionContextID)
+		fc.isolated = make(map[cdp.FrameID]runtime.ExecutionContextID)
+		fc.frames = make(map[runtime.ExecutionContextID]cdp.FrameID)
+	}
+}
+
+// isolatedContext 返回 frame 中已创建的隔离环境
+func (fc *frameContexts) isolatedContext(frameID cdp.FrameID) (runtime.ExecutionContextID, bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	id, ok := fc.isolated[frameID]
+	return id, ok
+}
+
+// setIsolated 记录 frame 中新创建的隔离环境
+func (fc *frameContexts) setIsolated(frameID cdp.FrameID, id runtime.ExecutionContextID) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.isolated[frameID] = id
+	fc.frames[id] = frameID
+}
+
+// dropIsolated 丢弃 frame 的隔离环境缓存，缓存已被替换为其他上下文时不做处理
+func (fc *frameContexts) dropIsolated(frameID cdp.FrameID, id runtime.ExecutionContextID) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.isolated[frameID] == id {
+		delete(fc.isolated, frameID)
+		delete(fc.frames, id)
+	}
+}
+
+// waitMain 返回 frame 默认执行上下文的 ID，frame 正在导航时最多等待 frameContextWait
+func (fc *frameContexts) waitMain(ctx context.Context, frameID cdp.FrameID) (runtime.ExecutionContextID, error) {
+	deadline := time.Now().Add(frameContextWait)
+	for {
+		fc.mu.Lock()
+		id, ok := fc.main[frameID]
+		fc.mu.Unlock()
+		if ok {
+			return id, nil
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("未找到 frame 的执行上下文: %s", frameID)
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// EvaluateIsolated 在主 frame 的隔离环境中执行 JS，结果写入 out，out 为 nil 时忽略结果
+// 隔离环境与页面共享 DOM，但拥有独立的全局对象，页面脚本无法观察或篡改注入的代码，
+// 也不受页面 Trusted Types 策略的限制。同一文档内多次调用复用同一个隔离环境，导航后重新创建
+func (bi *BrowserInstance) EvaluateIsolated(expr string, out interface{}) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	contexts, err := bi.pageFrameContexts()
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		frameID := tree.Frame.ID
+		contextID, ok := contexts.isolatedContext(frameID)
+		if !ok {
+			contextID, err = page.CreateIsolatedWorld(frameID).
+				WithWorldName(isolatedWorldName).
+				Do(ctx)
+			if err != nil {
+				return err
+			}
+			contexts.setIsolated(frameID, contextID)
+		}
+		res, exp, err := runtime.Evaluate(expr).
+			WithContextID(contextID).
+			WithReturnByValue(true).
+			WithAwaitPromise(true).
+			Do(ctx)
+		if err != nil {
+			// 隔离环境可能已随文档销毁而事件尚未到达，丢弃缓存，下次调用重新创建
+			contexts.dropIsolated(frameID, contextID)
+			return err
+		}
+		if exp != nil {
+			return exp
+		}
+		if out == nil || res.Value == nil {
+			return nil
+		}
+		return json.Unmarshal(res.Value, out)
+	}))
+}
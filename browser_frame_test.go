@@ -0,0 +1,50 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/runtime"
+	"testing"
+)
+
+func TestFrameContexts_TracksDefaultContext(t *testing.T) {
+	fc := newFrameContexts()
+	fc.handle(&runtime.EventExecutionContextCreated{Context: &runtime.ExecutionContextDescription{
+		ID:      1,
+		AuxData: []byte(`{"frameId":"frame","isDefault":true}`),
+	}})
+	fc.handle(&runtime.EventExecutionContextCreated{Context: &runtime.ExecutionContextDescription{
+		ID:      2,
+		AuxData: []byte(`{"frameId":"frame","isDefault":false}`),
+	}})
+
+	id, err := fc.waitMain(context.Background(), "frame")
+	if err != nil || id != 1 {
+		t.Fatalf("waitMain 返回 %v, %v; 期望默认执行上下文 1", id, err)
+	}
+
+	fc.handle(&runtime.EventExecutionContextDestroyed{ExecutionContextID: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = fc.waitMain(ctx, "frame"); err == nil {
+		t.Fatal("默认执行上下文销毁后不应再返回它")
+	}
+}
+
+func TestFrameContexts_DropsIsolatedOnDestroy(t *testing.T) {
+	fc := newFrameContexts()
+	fc.setIsolated("frame", 3)
+	if id, ok := fc.isolatedContext("frame"); !ok || id != 3 {
+		t.Fatalf("isolatedContext 返回 %v, %v; 期望复用隔离环境 3", id, ok)
+	}
+
+	fc.handle(&runtime.EventExecutionContextDestroyed{ExecutionContextID: 3})
+	if _, ok := fc.isolatedContext("frame"); ok {
+		t.Fatal("隔离环境销毁后应丢弃缓存")
+	}
+
+	fc.setIsolated("frame", 4)
+	fc.handle(&runtime.EventExecutionContextsCleared{})
+	if _, ok := fc.isolatedContext("frame"); ok {
+		t.Fatal("执行上下文全部清除后应丢弃缓存")
+	}
+}
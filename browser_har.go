@@ -0,0 +1,349 @@
+package browsers
+
+import (
+	"context"
+	"fmt"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HAR 是 HAR 1.2 格式的最小子集，可直接 json.Marshal 导出
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog HAR 的 log 节点
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator 记录生成 HAR 的工具
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry 一次请求/响应记录
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"` // ISO 8601 格式的开始时间
+	Time            float64     `json:"time"`            // 总耗时，毫秒
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+// HARRequest 请求信息
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []HARCookie     `json:"cookies"`
+	Headers     []HARHeader     `json:"headers"`
+	QueryString []HARQueryParam `json:"queryString"`
+	HeadersSize int64           `json:"headersSize"`
+	BodySize    int64           `json:"bodySize"`
+}
+
+// HARResponse 响应信息
+type HARResponse struct {
+	Status      int64       `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []HARCookie `json:"cookies"`
+	Headers     []HARHeader `json:"headers"`
+	Content     HARContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+	Error       string      `json:"_error,omitempty"` // 请求失败时的错误信息（自定义字段）
+}
+
+// HARHeader 头部键值对
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie 请求或响应中的 cookie，请求 cookie 只有 Name 和 Value
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"` // ISO 8601 格式的过期时间
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// HARQueryParam URL 中的查询参数
+type HARQueryParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARContent 响应体信息
+type HARContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// HARTimings 各阶段耗时，毫秒，无法测量（如请求失败、没有收到响应）时为 0
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harRecorder 收集网络事件并拼装成 HAR 记录
+type harRecorder struct {
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	entries []*harPending                     // 按请求发出的顺序排列
+	pending map[network.RequestID]*harPending // 尚未结束的请求
+}
+
+type harPending struct {
+	entry    HAREntry
+	started  time.Time // 请求开始的单调时间，用于计算耗时
+	received time.Time // 收到响应头的单调时间
+}
+
+// StartRecording 开始记录网络请求，重复调用会丢弃之前未导出的记录
+func (bi *BrowserInstance) StartRecording() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithCancel(bi.Ctx)
+	rec := &harRecorder{
+		cancel:  cancel,
+		pending: make(map[network.RequestID]*harPending),
+	}
+	chromedp.ListenTarget(ctx, rec.handle)
+
+	bi.harMu.Lock()
+	old := bi.har
+	bi.har = rec
+	bi.harMu.Unlock()
+
+	if old != nil {
+		old.cancel()
+	}
+	return nil
+}
+
+// StopRecording 停止记录并返回收集到的 HAR，未开始记录时返回 nil
+func (bi *BrowserInstance) StopRecording() *HAR {
+	bi.harMu.Lock()
+	rec := bi.har
+	bi.har = nil
+	bi.harMu.Unlock()
+
+	if rec == nil {
+		return nil
+	}
+	rec.cancel()
+	return rec.export()
+}
+
+func (r *harRecorder) handle(ev interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		// 重定向会复用同一个 RequestID，先把上一跳的响应补全
+		if p, ok := r.pending[ev.RequestID]; ok && ev.RedirectResponse != nil {
+			p.setResponse(ev.RedirectResponse)
+			p.finish(ev.Timestamp, ev.RedirectResponse.EncodedDataLength)
+		}
+		p := &harPending{
+			entry: HAREntry{
+				Request: HARRequest{
+					Method:      ev.Request.Method,
+					URL:         ev.Request.URL + ev.Request.URLFragment,
+					Cookies:     harRequestCookies(ev.Request.Headers),
+					Headers:     harHeaders(ev.Request.Headers),
+					QueryString: harQueryString(ev.Request.URL),
+					HeadersSize: -1,
+					BodySize:    -1,
+				},
+				Response: HARResponse{
+					Cookies:     []HARCookie{},
+					Headers:     []HARHeader{},
+					HeadersSize: -1,
+					BodySize:    -1,
+				},
+			},
+		}
+		if ev.WallTime != nil {
+			p.entry.StartedDateTime = ev.WallTime.Time().Format(time.RFC3339Nano)
+		}
+		if ev.Timestamp != nil {
+			p.started = ev.Timestamp.Time()
+		}
+		r.pending[ev.RequestID] = p
+		r.entries = append(r.entries, p)
+
+	case *network.EventResponseReceived:
+		if p, ok := r.pending[ev.RequestID]; ok {
+			p.setResponse(ev.Response)
+			if ev.Timestamp != nil {
+				p.received = ev.Timestamp.Time()
+			}
+		}
+
+	case *network.EventLoadingFinished:
+		if p, ok := r.pending[ev.RequestID]; ok {
+			p.finish(ev.Timestamp, ev.EncodedDataLength)
+			delete(r.pending, ev.RequestID)
+		}
+
+	case *network.EventLoadingFailed:
+		if p, ok := r.pending[ev.RequestID]; ok {
+			p.entry.Response.Error = ev.ErrorText
+			p.finish(ev.Timestamp, 0)
+			delete(r.pending, ev.RequestID)
+		}
+	}
+}
+
+func (p *harPending) setResponse(resp *network.Response) {
+	p.entry.Response.Status = resp.Status
+	p.entry.Response.StatusText = resp.StatusText
+	p.entry.Response.HTTPVersion = resp.Protocol
+	p.entry.Response.Headers = harHeaders(resp.Headers)
+	p.entry.Response.Cookies = harResponseCookies(resp.Headers)
+	p.entry.Response.Content.MimeType = resp.MimeType
+	p.entry.Request.HTTPVersion = resp.Protocol
+	if loc, ok := resp.Headers["Location"].(string); ok {
+		p.entry.Response.RedirectURL = loc
+	} else if loc, ok := resp.Headers["location"].(string); ok {
+		p.entry.Response.RedirectURL = loc
+	}
+}
+
+func (p *harPending) finish(ts *cdp.MonotonicTime, size float64) {
+	p.entry.Response.BodySize = int64(size)
+	p.entry.Response.Content.Size = int64(size)
+	if p.started.IsZero() || ts == nil {
+		return
+	}
+	end := ts.Time()
+	p.entry.Time = durationMs(end.Sub(p.started))
+	if !p.received.IsZero() {
+		p.entry.Timings.Send = 0
+		p.entry.Timings.Wait = durationMs(p.received.Sub(p.started))
+		p.entry.Timings.Receive = durationMs(end.Sub(p.received))
+	}
+}
+
+func (r *harRecorder) export() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h := &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "github.com/luoxk/browsers", Version: "1.0"},
+		Entries: make([]HAREntry, 0, len(r.entries)),
+	}}
+	for _, p := range r.entries {
+		h.Log.Entries = append(h.Log.Entries, p.entry)
+	}
+	return h
+}
+
+func harHeaders(headers network.Headers) []HARHeader {
+	out := make([]HARHeader, 0, len(headers))
+	for k, v := range headers {
+		out = append(out, HARHeader{Name: k, Value: fmt.Sprint(v)})
+	}
+	// map 遍历无序，排序保证导出结果稳定
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// harRequestCookies 解析请求头中的 Cookie，没有时返回空切片
+func harRequestCookies(headers network.Headers) []HARCookie {
+	out := []HARCookie{}
+	for k, v := range headers {
+		if !strings.EqualFold(k, "Cookie") {
+			continue
+		}
+		cookies, err := http.ParseCookie(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+		for _, c := range cookies {
+			out = append(out, HARCookie{Name: c.Name, Value: c.Value})
+		}
+	}
+	return out
+}
+
+// harResponseCookies 解析响应头中的 Set-Cookie，CDP 将多个 Set-Cookie 用换行拼接在一起
+func harResponseCookies(headers network.Headers) []HARCookie {
+	out := []HARCookie{}
+	for k, v := range headers {
+		if !strings.EqualFold(k, "Set-Cookie") {
+			continue
+		}
+		for _, line := range strings.Split(fmt.Sprint(v), "\n") {
+			c, err := http.ParseSetCookie(line)
+			if err != nil {
+				continue
+			}
+			cookie := HARCookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Path:     c.Path,
+				Domain:   c.Domain,
+				HTTPOnly: c.HttpOnly,
+				Secure:   c.Secure,
+			}
+			if !c.Expires.IsZero() {
+				cookie.Expires = c.Expires.Format(time.RFC3339)
+			}
+			out = append(out, cookie)
+		}
+	}
+	return out
+}
+
+// harQueryString 解析 URL 中的查询参数，按出现的顺序返回，没有时返回空切片
+func harQueryString(rawURL string) []HARQueryParam {
+	out := []HARQueryParam{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return out
+	}
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if n, err := url.QueryUnescape(name); err == nil {
+			name = n
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		out = append(out, HARQueryParam{Name: name, Value: value})
+	}
+	return out
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,48 @@
+package browsers
+
+import (
+	"encoding/json"
+	"github.com/chromedp/cdproto/network"
+	"strings"
+	"testing"
+)
+
+func TestHARRecorder_RequiredFields(t *testing.T) {
+	rec := &harRecorder{pending: make(map[network.RequestID]*harPending)}
+	rec.handle(&network.EventRequestWillBeSent{
+		RequestID: "1",
+		Request: &network.Request{
+			Method:  "GET",
+			URL:     "https://example.com/list?page=2&q=a%20b",
+			Headers: network.Headers{"Cookie": "sid=abc; theme=dark"},
+		},
+	})
+	rec.handle(&network.EventRequestWillBeSent{
+		RequestID: "2",
+		Request:   &network.Request{Method: "GET", URL: "https://example.com/"},
+	})
+	rec.handle(&network.EventLoadingFailed{RequestID: "2", ErrorText: "net::ERR_FAILED"})
+
+	har := rec.export()
+	req := har.Log.Entries[0].Request
+	if len(req.Cookies) != 2 || req.Cookies[0].Name != "sid" || req.Cookies[1].Value != "dark" {
+		t.Fatalf("请求 cookie 解析错误: %+v", req.Cookies)
+	}
+	if len(req.QueryString) != 2 || req.QueryString[0].Name != "page" || req.QueryString[1].Value != "a b" {
+		t.Fatalf("查询参数解析错误: %+v", req.QueryString)
+	}
+
+	data, err := json.Marshal(har.Log.Entries[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	for _, want := range []string{`"cookies":[],"headers":[],"queryString":[]`, `"timings":{"send":0,"wait":0,"receive":0}`} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("HAR 记录应包含 %s，实际为 %s", want, s)
+		}
+	}
+	if strings.Count(s, `"cookies":[]`) != 2 {
+		t.Fatalf("请求和响应都应输出空的 cookies 数组，实际为 %s", s)
+	}
+}
@@ -0,0 +1,45 @@
+package browsers
+
+import (
+	"github.com/luoxk/chromedp"
+)
+
+// NewIncognitoTab 在同一个 Chrome 进程中创建一个隔离的浏览器上下文（类似无痕窗口）并打开一个标签页
+// 新实例拥有独立的 cookie 和存储，关闭时销毁该浏览器上下文，不会关闭 Chrome 进程。
+// 返回的实例不由 BrowserController 管理，ID 为 0；父实例关闭时它也会随之关闭。
+func (bi *BrowserInstance) NewIncognitoTab() (*BrowserInstance, error) {
+	return bi.newIsolated(0)
+}
+
+// newIsolated 创建隔离的浏览器上下文并返回以 id 标识的实例
+func (bi *BrowserInstance) newIsolated(id int) (*BrowserInstance, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	ctx, cancel := chromedp.NewContext(bi.Ctx, chromedp.WithNewBrowserContext())
+	frameContexts := trackFrameContexts(ctx)
+	// 第一次 Run 时才会真正创建浏览器上下文和标签页
+	if err := chromedp.Run(ctx, chromedp.Navigate("about:blank")); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if bi.options.AutoAcceptBeforeUnload {
+		acceptBeforeUnload(ctx)
+	}
+	if bi.options.ConsoleWriter != nil {
+		chromedp.ListenTarget(ctx, newConsoleWriter(bi.options.ConsoleWriter, id).handle)
+	}
+
+	instance := NewBrowserInstance(id, chromedp.FromContext(ctx), ctx, cancel)
+	instance.DefaultTimeout = bi.DefaultTimeout
+	// 沿用父实例的全部启动参数（Referer、RemoteURL 等），子实例共用同一个浏览器进程，不会按这些参数重新启动
+	instance.options = bi.options
+	instance.userAgentRotator = bi.userAgentRotator
+	instance.frameContexts = frameContexts
+	instance.logEvent(EventLaunched, "isolated", nil)
+	instance.trackResponses()
+	instance.watchCrash()
+	return instance, nil
+}
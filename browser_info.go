@@ -0,0 +1,168 @@
+package browsers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/luoxk/chromedp"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BrowserVersion 获取浏览器版本和协议信息
+func (bi *BrowserInstance) BrowserVersion() (product, revision, protocolVersion, userAgent string, err error) {
+	if bi.Closed() {
+		return "", "", "", "", ErrBrowserClosed
+	}
+	err = chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// Browser.getVersion 属于浏览器级命令，需要使用 Browser 执行器
+		c := chromedp.FromContext(ctx)
+		var err error
+		protocolVersion, product, revision, userAgent, _, err = browser.GetVersion().Do(cdp.WithExecutor(ctx, c.Browser))
+		return err
+	}))
+	return
+}
+
+// DevToolsURL 返回浏览器的 DevTools WebSocket 地址，便于外部工具连接到本包启动的实例
+// 本地启动的实例从用户目录下的 DevToolsActivePort 文件读取，远程实例从 RemoteURL 解析
+func (bi *BrowserInstance) DevToolsURL() (string, error) {
+	if bi.Closed() {
+		return "", ErrBrowserClosed
+	}
+	if remote := bi.options.RemoteURL; remote != "" {
+		if strings.HasPrefix(remote, "ws://") || strings.HasPrefix(remote, "wss://") {
+			return remote, nil
+		}
+		return remoteDevToolsURL(bi.Ctx, remote)
+	}
+
+	// 命令行中带有实际使用的用户目录，未指定 UserDir 时为 chromedp 创建的临时目录
+	var args []string
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		args, err = browser.GetBrowserCommandLine().Do(cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser))
+		return err
+	}))
+	if err != nil {
+		return "", fmt.Errorf("获取浏览器命令行失败: %w", err)
+	}
+	var dir string
+	for _, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "--user-data-dir="); ok {
+			dir = v
+		}
+	}
+	if dir == "" {
+		return "", fmt.Errorf("浏览器命令行中没有 --user-data-dir")
+	}
+
+	// DevToolsActivePort 第一行为端口，第二行为浏览器端点路径
+	f, err := os.Open(filepath.Join(dir, "DevToolsActivePort"))
+	if err != nil {
+		return "", fmt.Errorf("读取 DevToolsActivePort 失败: %w", err)
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() && len(lines) < 2 {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if len(lines) < 2 || lines[0] == "" || lines[1] == "" {
+		return "", fmt.Errorf("DevToolsActivePort 格式错误")
+	}
+	return "ws://127.0.0.1:" + lines[0] + lines[1], nil
+}
+
+// remoteDevToolsURL 通过 /json/version 将 http 地址解析为 WebSocket 地址
+func remoteDevToolsURL(ctx context.Context, base string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+"/json/version", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求 /json/version 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	var version struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return "", fmt.Errorf("解析 /json/version 失败: %w", err)
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("/json/version 中没有 webSocketDebuggerUrl")
+	}
+	return version.WebSocketDebuggerURL, nil
+}
+
+// TargetCount 返回与当前实例处于同一浏览器上下文中的标签页数量（含弹出窗口），
+// 不计扩展、Service Worker 等后台页面，可用于发现未关闭的弹窗
+func (bi *BrowserInstance) TargetCount() (int, error) {
+	if bi.Closed() {
+		return 0, ErrBrowserClosed
+	}
+
+	var count int
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c := chromedp.FromContext(ctx)
+		bctx := cdp.WithExecutor(ctx, c.Browser)
+		self, err := target.GetTargetInfo().WithTargetID(c.Target.TargetID).Do(bctx)
+		if err != nil {
+			return err
+		}
+		infos, err := target.GetTargets().Do(bctx)
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			if info.Type == "page" && info.BrowserContextID == self.BrowserContextID {
+				count++
+			}
+		}
+		return nil
+	}))
+	return count, err
+}
+
+// PID 返回本地启动的 Chrome 主进程 ID，可用于设置系统资源限制、发送信号或关联系统监控
+// 连接远程浏览器（RemoteURL）或 Adopt 接管的远程上下文没有本地进程，返回错误
+func (bi *BrowserInstance) PID() (int, error) {
+	if bi.Browser == nil || bi.Browser.Browser == nil || bi.Browser.Browser.Process() == nil {
+		return 0, fmt.Errorf("浏览器进程不在本机，无法获取进程 ID")
+	}
+	return bi.Browser.Browser.Process().Pid, nil
+}
+
+// ProcessStats Chrome 进程树的资源占用
+type ProcessStats struct {
+	PID       int           // 主进程 ID
+	Processes int           // 统计的进程数量，包括渲染、GPU 等子进程
+	RSS       uint64        // 常驻内存总量，字节
+	CPUTime   time.Duration // 累计占用的 CPU 时间（用户态 + 内核态）
+}
+
+// RSSMB 以 MB 为单位返回常驻内存
+func (s *ProcessStats) RSSMB() int {
+	return int(s.RSS / (1 << 20))
+}
+
+// ResourceUsage 统计 Chrome 主进程及其所有子进程的内存和 CPU 占用
+func (bi *BrowserInstance) ResourceUsage() (*ProcessStats, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+	pid, err := bi.PID()
+	if err != nil {
+		return nil, err
+	}
+	return processTreeStats(pid)
+}
@@ -2,14 +2,22 @@ package browsers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/cdproto/runtime"
 	"github.com/luoxk/chromedp"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // BrowserInstance 表示一个浏览器实例
@@ -18,32 +26,123 @@ type BrowserInstance struct {
 	Browser *chromedp.Context  // 浏览器实例
 	Ctx     context.Context    // 上下文
 	Cancel  context.CancelFunc // 取消函数
+	Labels  map[string]string  // 实例标签
 	closed  bool               // 标记浏览器是否已关闭
 	mu      sync.RWMutex       // 用于保护 closed 状态的互斥锁
+
+	DefaultTimeout time.Duration // Goto、GetCookies、SabaFetch 的默认超时，为 0 时不限制
+
+	// ResponseBodyClient 非 nil 时，InterceptJSON 遇到超过 8MB 或 CDP 无法读取的响应体，
+	// 会携带浏览器的请求头和 cookie 通过该客户端重新请求
+	ResponseBodyClient *http.Client
+
+	harMu sync.Mutex   // 保护 har
+	har   *harRecorder // 正在进行的网络记录
+
+	wsMu sync.Mutex // 保护 ws
+	ws   *wsCapture // WebSocket 帧记录
+
+	downloadMu  sync.Mutex // 保护 downloadDir
+	downloadDir string     // 下载文件的保存目录
+
+	proxyMu       sync.Mutex         // 保护 proxyCancel
+	proxyCancel   context.CancelFunc // 停止运行时代理转发
+	fetchAtLaunch bool               // 启动时是否已因 HookFunc 或 ResponseMocks 启用 Fetch 域
+
+	userAgentRotator func() string  // 每次 Goto 前调用以获取新的 UA
+	options          BrowserOptions // 启动参数，用于重新启动实例
+	parentID         int            // LaunchIsolated 创建的实例所属的父实例 ID，独立进程为 0
+	adopted          bool           // 是否由 Adopt 接管，没有启动参数，不能重新启动
+
+	pool atomic.Pointer[BrowserPool] // 启动该实例的 BrowserPool，内存回收时用于放回替代实例
+
+	closeReason CloseReason // 实例关闭的原因，由 mu 保护
+	events      eventLog    // 最近的生命周期事件
+
+	paused atomic.Bool // 是否被 PauseAll 禁用了脚本执行
+
+	responses *responseLRU // 最近加载完成的响应，供 ResponseBody 查找
+
+	statsEnabled   atomic.Bool  // 是否已调用 EnableRequestStats
+	requestsTotal  atomic.Int64 // 发出的请求数
+	requestsFailed atomic.Int64 // 失败的请求数
+
+	crashMu       sync.Mutex // 保护 crashHandlers
+	crashHandlers []func()   // OnCrash 注册的回调
+
+	frameTargets    sync.Map       // 跨进程 iframe 的 frame ID -> 附加到其 target 的 *frameSession
+	frameContextsMu sync.Mutex     // 保护 frameContexts
+	frameContexts   *frameContexts // 页面会话中各 frame 的执行上下文，由 pageFrameContexts 延迟创建
+
+	opSem chan struct{} // 操作锁，容量为 1，串行化 Goto、GetCookies、SabaFetch 等操作，避免多个 goroutine 交错发送命令
+	busy  atomic.Bool   // 是否有操作正在执行
 }
 
+// CloseReason 实例关闭的原因
+type CloseReason string
+
+const (
+	CloseReasonExplicit     CloseReason = "explicit"      // 调用了 Close 或 BrowserController 的关闭方法
+	CloseReasonContextDone  CloseReason = "context done"  // 上下文结束，通常是浏览器崩溃或连接断开
+	CloseReasonCrashed      CloseReason = "crashed"       // 渲染进程崩溃
+	CloseReasonMemoryLimit  CloseReason = "memory limit"  // 内存超过 BrowserController.MaxMemoryMB 被回收
+	CloseReasonParentClosed CloseReason = "parent closed" // LaunchIsolated 创建的子实例随父实例关闭
+)
+
 // NewBrowserInstance 创建一个新的浏览器实例
 func NewBrowserInstance(id int, browser *chromedp.Context, ctx context.Context, cancel context.CancelFunc) *BrowserInstance {
-	instance := &BrowserInstance{
-		ID:      id,
-		Browser: browser,
-		Ctx:     ctx,
-		Cancel:  cancel,
-		closed:  false,
-	}
+	instance := NewBrowserInstanceNoMonitor(id, browser, ctx, cancel)
 
 	// 启动一个 goroutine 来监听上下文的完成
 	go instance.monitorContext()
 	return instance
 }
 
+// NewBrowserInstanceNoMonitor 创建一个不监听上下文的浏览器实例
+//
+// NewBrowserInstance 会为每个实例启动一个 goroutine，在上下文结束（如浏览器崩溃）时自动 Close。
+// 大量创建短生命周期实例且自行管理关闭的调用方可以使用此函数省去该 goroutine，
+// 代价是浏览器意外退出后 Closed 仍返回 false，需要调用方显式 Close 才能释放资源。
+func NewBrowserInstanceNoMonitor(id int, browser *chromedp.Context, ctx context.Context, cancel context.CancelFunc) *BrowserInstance {
+	return &BrowserInstance{
+		ID:        id,
+		Browser:   browser,
+		Ctx:       ctx,
+		Cancel:    cancel,
+		Labels:    make(map[string]string),
+		closed:    false,
+		responses: newResponseLRU(),
+		opSem:     make(chan struct{}, 1),
+	}
+}
+
 // monitorContext 监听上下文的完成信号
 func (bi *BrowserInstance) monitorContext() {
 	<-bi.Ctx.Done()
 	// 上下文完成时自动关闭浏览器实例
+	bi.setCloseReason(CloseReasonContextDone)
 	bi.Close()
 }
 
+// setCloseReason 在实例关闭前记录关闭原因，已记录或已关闭时忽略
+func (bi *BrowserInstance) setCloseReason(reason CloseReason) {
+	bi.mu.Lock()
+	defer bi.mu.Unlock()
+	if !bi.closed && bi.closeReason == "" {
+		bi.closeReason = reason
+	}
+}
+
+// ClosedReason 返回实例关闭的原因，未关闭时 ok 为 false
+func (bi *BrowserInstance) ClosedReason() (reason string, ok bool) {
+	bi.mu.RLock()
+	defer bi.mu.RUnlock()
+	if !bi.closed {
+		return "", false
+	}
+	return string(bi.closeReason), true
+}
+
 func (bi *BrowserInstance) WaitFor(cb func(ctx context.Context) error) (err error) {
 	return chromedp.Run(bi.Context(), chromedp.ActionFunc(func(ctx context.Context) error {
 		return cb(ctx)
@@ -62,6 +161,76 @@ func (b *BrowserInstance) CallJs2Str(eval string) string {
 	return ""
 }
 
+// CallJsInt 执行 JS 表达式并返回整数结果
+func (bi *BrowserInstance) CallJsInt(eval string) (int64, error) {
+	var v int64
+	err := bi.callJs(eval, &v)
+	return v, err
+}
+
+// CallJsFloat 执行 JS 表达式并返回浮点数结果
+func (bi *BrowserInstance) CallJsFloat(eval string) (float64, error) {
+	var v float64
+	err := bi.callJs(eval, &v)
+	return v, err
+}
+
+// CallJsBool 执行 JS 表达式并返回布尔结果
+func (bi *BrowserInstance) CallJsBool(eval string) (bool, error) {
+	var v bool
+	err := bi.callJs(eval, &v)
+	return v, err
+}
+
+// EvaluateRaw 执行 JS 表达式并返回未解析的 JSON 结果，结果为 undefined 时返回 null
+func (bi *BrowserInstance) EvaluateRaw(expr string) (json.RawMessage, error) {
+	var raw []byte
+	if err := bi.callJs(expr, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return json.RawMessage(raw), nil
+}
+
+// EvaluateBinary 执行返回 base64 字符串的 JS 表达式（如 canvas.toDataURL()）并解码为原始字节
+// 表达式可以返回 Promise；结果带有 data:...;base64, 前缀时会先去掉
+func (bi *BrowserInstance) EvaluateBinary(expr string) ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+	var encoded string
+	err := chromedp.Run(bi.Ctx, chromedp.Evaluate(expr, &encoded, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("执行 JS 失败: %w", err)
+	}
+	if strings.HasPrefix(encoded, "data:") {
+		if i := strings.Index(encoded, ","); i >= 0 {
+			encoded = encoded[i+1:]
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("解码 base64 失败: %v", err)
+	}
+	return data, nil
+}
+
+// callJs 执行 JS 表达式并将结果反序列化到 res，结果类型不匹配时返回错误
+func (bi *BrowserInstance) callJs(eval string, res interface{}) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	err := chromedp.Run(bi.Ctx, chromedp.Evaluate(eval, res))
+	if err != nil {
+		return fmt.Errorf("执行 JS 失败: %w", err)
+	}
+	return nil
+}
+
 // Close 关闭浏览器实例
 func (bi *BrowserInstance) Close() {
 	bi.mu.Lock()
@@ -71,21 +240,79 @@ func (bi *BrowserInstance) Close() {
 		return
 	}
 	// 1. 确保取消所有挂起的浏览器任务
-	if err := chromedp.Cancel(bi.Ctx); err != nil {
-		log.Printf("Failed to cancel chromedp context for browser instance %d: %v", bi.ID, err)
+	// 上下文已经结束（浏览器崩溃或 monitorContext 触发）时 chromedp.Cancel 只会返回无意义的错误，跳过
+	// 远程实例不能走 chromedp.Cancel：它可能向共享的远程浏览器发送 Browser.close，
+	// 只取消上下文即可关闭自己的标签页和浏览器上下文并断开连接
+	if bi.Ctx.Err() == nil && chromedp.FromContext(bi.Ctx) != nil && bi.options.RemoteURL == "" {
+		if err := chromedp.Cancel(bi.Ctx); err != nil {
+			log.Printf("Failed to cancel chromedp context for browser instance %d: %v", bi.ID, err)
+		}
 	}
 	// 2. 释放上下文并关闭浏览器
 	if bi.Cancel != nil {
 		bi.Cancel() // 取消浏览器上下文
 
 	}
-	// 3. 标记浏览器已关闭
+	// 3. 标记浏览器已关闭，没有记录其他原因时视为主动关闭
 	bi.closed = true
+	if bi.closeReason == "" {
+		bi.closeReason = CloseReasonExplicit
+	}
+	bi.events.add(EventClosed, string(bi.closeReason))
 	// 4. 记录日志 (可选)
 	log.Printf("Browser instance %d has been closed", bi.ID)
 	return
 }
 
+// Busy 返回实例当前是否有操作正在执行
+func (bi *BrowserInstance) Busy() bool {
+	return bi.busy.Load()
+}
+
+// acquire 获取操作锁并标记为忙碌，返回释放函数
+// 只用于不会回调用户代码、不会嵌套调用其他加锁方法的操作，否则会死锁
+func (bi *BrowserInstance) acquire() (release func()) {
+	bi.opSem <- struct{}{}
+	bi.busy.Store(true)
+	return bi.release
+}
+
+// acquireCtx 与 acquire 相同，但等待操作锁的时间也受 ctx 限制，ctx 结束时返回 ctx.Err()
+func (bi *BrowserInstance) acquireCtx(ctx context.Context) (release func(), err error) {
+	select {
+	case bi.opSem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	bi.busy.Store(true)
+	return bi.release, nil
+}
+
+// tryAcquire 尝试获取操作锁，实例忙碌时立即返回 false
+func (bi *BrowserInstance) tryAcquire() (release func(), ok bool) {
+	select {
+	case bi.opSem <- struct{}{}:
+	default:
+		return nil, false
+	}
+	bi.busy.Store(true)
+	return bi.release, true
+}
+
+// release 释放 acquire、acquireCtx 或 tryAcquire 获取的操作锁
+func (bi *BrowserInstance) release() {
+	bi.busy.Store(false)
+	<-bi.opSem
+}
+
+// runCtx 返回带 DefaultTimeout 的上下文，用完需调用 cancel
+func (bi *BrowserInstance) runCtx() (context.Context, context.CancelFunc) {
+	if bi.DefaultTimeout > 0 {
+		return context.WithTimeout(bi.Ctx, bi.DefaultTimeout)
+	}
+	return context.WithCancel(bi.Ctx)
+}
+
 func (bi *BrowserInstance) Context() context.Context {
 	fmt.Println("get Context")
 	return bi.Ctx
@@ -99,36 +326,198 @@ func (bi *BrowserInstance) Closed() bool {
 }
 
 func (bi *BrowserInstance) Goto(url string, beforeNavigate ...func(ctx context.Context) error) error {
+	return bi.GotoWithReferer(url, bi.options.Referer, beforeNavigate...)
+}
+
+// GotoWithReferer 与 Goto 相同，但导航请求携带指定的 Referer，为空时不设置
+func (bi *BrowserInstance) GotoWithReferer(url, referer string, beforeNavigate ...func(ctx context.Context) error) error {
+	ctx, cancel := bi.runCtx()
+	defer cancel()
+	return bi.gotoCtx(ctx, url, referer, beforeNavigate...)
+}
+
+// gotoCtx 在 ctx 下执行 GotoWithReferer 的导航，供需要自行控制超时的调用方使用
+func (bi *BrowserInstance) gotoCtx(ctx context.Context, url, referer string, beforeNavigate ...func(ctx context.Context) error) error {
 	// 如果浏览器已关闭，直接返回错误
 	if bi.Closed() {
-		return fmt.Errorf("浏览器已关闭")
+		return ErrBrowserClosed
 	}
-	// 执行导航操作
-	return chromedp.Run(bi.Ctx,
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			for _, cb := range beforeNavigate {
-				err := cb(ctx)
-				if err != nil {
+
+	// 用户回调可能调用实例的其他加锁方法，需要在获取操作锁之前执行，否则会死锁
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if bi.userAgentRotator != nil {
+			if ua := bi.userAgentRotator(); ua != "" {
+				if err := emulation.SetUserAgentOverride(ua).Do(ctx); err != nil {
 					return err
 				}
 			}
+		}
+		for _, cb := range beforeNavigate {
+			err := cb(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		bi.logEvent(EventNavigated, url, err)
+		return err
+	}
+
+	navigate := chromedp.Action(chromedp.Navigate(url))
+	if referer != "" {
+		navigate = navigateWithReferrer(url, referer)
+	}
+	// 执行导航操作
+	release, err := bi.acquireCtx(ctx)
+	if err != nil {
+		bi.logEvent(EventNavigated, url, err)
+		return err
+	}
+	err = chromedp.Run(ctx, navigate)
+	release()
+	bi.logEvent(EventNavigated, url, err)
+	return err
+}
+
+// navigateWithReferrer 携带 Referer 导航并等待 load 事件
+// chromedp.Navigate 不支持设置 Referer，这里直接调用 page.Navigate
+func navigateWithReferrer(url, referrer string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		lctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		loaded := make(chan struct{}, 1)
+		chromedp.ListenTarget(lctx, func(ev interface{}) {
+			if _, ok := ev.(*page.EventLoadEventFired); ok {
+				select {
+				case loaded <- struct{}{}:
+				default:
+				}
+			}
+		})
+
+		_, loaderID, errorText, err := page.Navigate(url).WithReferrer(referrer).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if errorText != "" {
+			return fmt.Errorf("page load error %s", errorText)
+		}
+		// 同文档导航（如只改变 hash）没有 loaderID，也不会触发 load 事件
+		if loaderID == "" {
 			return nil
-		}),
-		chromedp.Navigate(url),
-	)
+		}
+		select {
+		case <-loaded:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// StopLoading 中止当前页面的加载，保留已加载的 DOM
+func (bi *BrowserInstance) StopLoading() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, page.StopLoading())
+}
+
+// BypassCSP 开启或关闭对页面 Content-Security-Policy 的绕过，使注入的脚本不被拦截
+// 只对之后加载的文档生效，需要在 Goto 之前调用
+func (bi *BrowserInstance) BypassCSP(enabled bool) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, page.SetBypassCSP(enabled))
+}
+
+// GotoUntilDOMReady 导航到 url，DOMContentLoaded 触发后立即中止剩余资源的加载
+// 适合广告脚本拖慢 load 事件的页面，超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) GotoUntilDOMReady(url string, timeout time.Duration) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout)
+	defer cancel()
+
+	ready := make(chan struct{}, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if _, ok := ev.(*page.EventDomContentEventFired); ok {
+			select {
+			case ready <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	// page.Navigate 不等待 load 事件，与 chromedp.Navigate 不同
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, _, errorText, err := page.Navigate(url).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if errorText != "" {
+			return fmt.Errorf("导航失败: %s", errorText)
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		if bi.Ctx.Err() == nil {
+			return fmt.Errorf("%w: 等待 DOMContentLoaded: %s", ErrWaitTimeout, url)
+		}
+		return ctx.Err()
+	}
+	return bi.StopLoading()
 }
 
 func (bi *BrowserInstance) GetCookies() ([]*http.Cookie, error) {
+	return bi.GetCookiesCtx(context.Background())
+}
+
+// GetCookiesCtx 与 GetCookies 相同，但在 ctx 结束时立即返回，
+// 用于健康检查等不能被卡死的 CDP 连接阻塞的场景
+func (bi *BrowserInstance) GetCookiesCtx(ctx context.Context) ([]*http.Cookie, error) {
+	cookies, err := bi.getCookies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toHTTPCookies(cookies), nil
+}
+
+// GetCookiesWithPartitionKey 与 GetCookies 相同，但返回的 cookie 附带分区键
+func (bi *BrowserInstance) GetCookiesWithPartitionKey() ([]*Cookie, error) {
+	return bi.getCookies(context.Background())
+}
+
+func (bi *BrowserInstance) getCookies(ctx context.Context) ([]*Cookie, error) {
 	// 检查浏览器是否已关闭
 	if bi.Closed() {
-		return nil, fmt.Errorf("浏览器已关闭")
+		return nil, ErrBrowserClosed
 	}
 
+	defer bi.acquire()()
+
 	// 创建一个容器来接收 cookies
-	var cookies []*http.Cookie
+	var cookies []*Cookie
+
+	runCtx, cancel := bi.runCtx()
+	defer cancel()
+	// 调用方的 ctx 结束时一并取消本次调用
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
 
 	// 获取 cookies
-	err := chromedp.Run(bi.Ctx,
+	err := chromedp.Run(runCtx,
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			cks, err := network.GetCookies().Do(ctx)
 			if err != nil {
@@ -139,7 +528,10 @@ func (bi *BrowserInstance) GetCookies() ([]*http.Cookie, error) {
 		}),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("获取 cookies 失败: %v", err)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, fmt.Errorf("获取 cookies 失败: %w", err)
 	}
 
 	// 返回获取到的 cookies
@@ -147,10 +539,38 @@ func (bi *BrowserInstance) GetCookies() ([]*http.Cookie, error) {
 }
 
 func (bi *BrowserInstance) SabaFetch(eval string) *BrowserResponse {
+	return bi.sabaFetch(eval, "")
+}
+
+// SabaFetchToken 与 SabaFetch 相同，额外执行 tokenExpr 提取令牌写入 BrowserResponse.Token
+// tokenExpr 中可以通过 res 访问 eval 的结果，例如 `JSON.parse(res.data).access_token`
+func (bi *BrowserInstance) SabaFetchToken(eval, tokenExpr string) *BrowserResponse {
+	return bi.sabaFetch(eval, tokenExpr)
+}
+
+func (bi *BrowserInstance) sabaFetch(eval, tokenExpr string) *BrowserResponse {
+	var token string
+	if tokenExpr != "" {
+		token = fmt.Sprintf(`if (c && typeof c === "object" && !c.error) {try {var t = await (async function(res) {return (%v);})(c);if (t != null) c.token = String(t);} catch (e) {c.error = "token: " + String(e);}}`, tokenExpr)
+	}
+
+	// 先创建超时上下文，等待操作锁的时间也计入 DefaultTimeout
+	ctx, cancel := bi.runCtx()
+	defer cancel()
+	release, err := bi.acquireCtx(ctx)
+	if err != nil {
+		return &BrowserResponse{Error: err.Error()}
+	}
+	defer release()
+	return evalFetch(ctx, eval, token)
+}
+
+// evalFetch 执行 sabaFetch 包装后的脚本，不获取操作锁，供等待类方法长时间 await 时使用
+func evalFetch(ctx context.Context, eval, token string) *BrowserResponse {
 	var data = make(map[string]*BrowserResponse)
-	err := chromedp.Run(bi.Ctx,
+	err := chromedp.Run(ctx,
 		chromedp.ActionFunc(func(ctx context.Context) error {
-			return chromedp.Evaluate(fmt.Sprintf(`(async function() {var c = %v;return {"dst":c};})()`, eval),
+			return chromedp.Evaluate(sabaFetchScript(eval, token),
 				&data,
 				func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
 					return p.WithAwaitPromise(true)
@@ -173,25 +593,51 @@ func (bi *BrowserInstance) SabaFetch(eval string) *BrowserResponse {
 	return b
 }
 
-func convertCookies(netCookies []*network.Cookie) []*http.Cookie {
-	httpCookies := []*http.Cookie{}
+// sabaFetchScript 生成 sabaFetch 执行的脚本
+// eval 保持 `var c = eval;` 的原始拼接方式，兼容带结尾分号或多条语句的写法，之后再 await 结果；
+// 同时捕获 Promise 的 reject，将原因写入 error 字段，而不是丢失整个结果
+func sabaFetchScript(eval, token string) string {
+	return fmt.Sprintf(`(async function() {try {var c = %v;c = await c;%v return {"dst":c};} catch (e) {return {"dst":{"error":String(e)}};}})()`, eval, token)
+}
+
+func convertCookies(netCookies []*network.Cookie) []*Cookie {
+	cookies := []*Cookie{}
 
 	for _, netCookie := range netCookies {
-		httpCookie := &http.Cookie{
-			Name:     netCookie.Name,
-			Value:    netCookie.Value,
-			Path:     netCookie.Path,
-			Domain:   netCookie.Domain,
-			Secure:   netCookie.Secure,
-			HttpOnly: netCookie.HTTPOnly,
+		cookie := &Cookie{Cookie: &http.Cookie{
+			Name:        netCookie.Name,
+			Value:       netCookie.Value,
+			Path:        netCookie.Path,
+			Domain:      netCookie.Domain,
+			Secure:      netCookie.Secure,
+			HttpOnly:    netCookie.HTTPOnly,
+			Partitioned: netCookie.PartitionKey != nil,
+		}}
+		if netCookie.PartitionKey != nil {
+			cookie.PartitionKey = netCookie.PartitionKey.TopLevelSite
 		}
 
-		httpCookies = append(httpCookies, httpCookie)
+		cookies = append(cookies, cookie)
 	}
 
+	return cookies
+}
+
+// toHTTPCookies 去掉分区键，返回其中的 http.Cookie
+func toHTTPCookies(cookies []*Cookie) []*http.Cookie {
+	httpCookies := make([]*http.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		httpCookies = append(httpCookies, c.Cookie)
+	}
 	return httpCookies
 }
 
+// jsString 将 Go 字符串转为可安全拼接进 JS 代码的字符串字面量
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 type BrowserResponse struct {
 	Data  string `json:"data,omitempty"`
 	Error string `json:"error,omitempty"`
@@ -204,3 +650,44 @@ func (this *BrowserResponse) Err() error {
 	}
 	return nil
 }
+
+// Path 将 Data 解析为 JSON，并按点分路径取值，例如 data.items.0.id
+// 字符串直接返回原值，其余类型返回其 JSON 表示
+func (this *BrowserResponse) Path(jsonPath string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(this.Data))
+	dec.UseNumber()
+	var cur interface{}
+	if err := dec.Decode(&cur); err != nil {
+		return "", fmt.Errorf("Data 不是合法的 JSON: %v", err)
+	}
+
+	if jsonPath != "" {
+		for _, key := range strings.Split(jsonPath, ".") {
+			switch node := cur.(type) {
+			case map[string]interface{}:
+				v, ok := node[key]
+				if !ok {
+					return "", fmt.Errorf("路径 %s 不存在: %s", jsonPath, key)
+				}
+				cur = v
+			case []interface{}:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(node) {
+					return "", fmt.Errorf("路径 %s 的数组下标无效: %s", jsonPath, key)
+				}
+				cur = node[i]
+			default:
+				return "", fmt.Errorf("路径 %s 不存在: %s", jsonPath, key)
+			}
+		}
+	}
+
+	if str, ok := cur.(string); ok {
+		return str, nil
+	}
+	b, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
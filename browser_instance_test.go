@@ -0,0 +1,145 @@
+package browsers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBrowserResponse_Path(t *testing.T) {
+	resp := &BrowserResponse{Data: `{"data":{"items":[{"id":42,"name":"a"},{"id":7,"tags":["x"]}],"ok":true,"token":"abc"}}`}
+
+	cases := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "data.token", want: "abc"},
+		{path: "data.items.0.id", want: "42"},
+		{path: "data.items.1.tags", want: `["x"]`},
+		{path: "data.ok", want: "true"},
+		{path: "data.items.2.id", wantErr: true},
+		{path: "data.items.x", wantErr: true},
+		{path: "data.missing", wantErr: true},
+		{path: "data.token.more", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := resp.Path(c.path)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("Path(%q) 应返回错误，实际得到 %q", c.path, got)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("Path(%q) = %q, %v; 期望 %q", c.path, got, err, c.want)
+		}
+	}
+
+	if _, err := (&BrowserResponse{Data: "not json"}).Path("a"); err == nil {
+		t.Error("Data 不是 JSON 时应返回错误")
+	}
+}
+
+func TestBrowserInstance_CloseIdempotent(t *testing.T) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	bi := NewBrowserInstance(1, nil, ctx, func() {
+		calls.Add(1)
+		cancelCtx()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bi.Close()
+		}()
+	}
+	// 同时触发 monitorContext 的关闭路径
+	cancelCtx()
+	wg.Wait()
+	bi.Close()
+
+	if !bi.Closed() {
+		t.Fatal("Close 之后 Closed 应返回 true")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Cancel 应只调用一次，实际调用 %d 次", n)
+	}
+}
+
+func TestBrowserInstance_ClosedReason(t *testing.T) {
+	bi := NewBrowserInstanceNoMonitor(1, nil, context.Background(), func() {})
+	if _, ok := bi.ClosedReason(); ok {
+		t.Fatal("未关闭时 ok 应为 false")
+	}
+	bi.Close()
+	if reason, ok := bi.ClosedReason(); !ok || reason != string(CloseReasonExplicit) {
+		t.Fatalf("主动关闭的原因应为 %q，实际为 %q", CloseReasonExplicit, reason)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bi = NewBrowserInstance(2, nil, ctx, cancel)
+	cancel()
+	for i := 0; i < 100 && !bi.Closed(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reason, ok := bi.ClosedReason(); !ok || reason != string(CloseReasonContextDone) {
+		t.Fatalf("上下文结束的原因应为 %q，实际为 %q", CloseReasonContextDone, reason)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	bi := NewBrowserInstanceNoMonitor(1, nil, context.Background(), func() {})
+	bi.Close()
+	if _, err := bi.GetCookies(); !errors.Is(err, ErrBrowserClosed) {
+		t.Fatalf("已关闭实例应返回 ErrBrowserClosed，实际为 %v", err)
+	}
+	if _, err := bi.Count("a"); !errors.Is(err, ErrBrowserClosed) {
+		t.Fatalf("已关闭实例应返回 ErrBrowserClosed，实际为 %v", err)
+	}
+
+	controller := NewBrowserController()
+	if _, err := controller.GetBrowserInstance(42); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("不存在的实例应返回 ErrInstanceNotFound，实际为 %v", err)
+	}
+	if err := controller.CloseBrowser(42); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("不存在的实例应返回 ErrInstanceNotFound，实际为 %v", err)
+	}
+}
+
+func TestSabaFetchScript_TrailingSemicolon(t *testing.T) {
+	// 结尾带分号的表达式必须作为完整语句拼接，不能包进 await (...) 中
+	script := sabaFetchScript("1+1;", "")
+	if !strings.Contains(script, "var c = 1+1;;c = await c;") {
+		t.Fatalf("脚本未按 var c = eval; 的方式拼接: %s", script)
+	}
+	if strings.Contains(script, "(1+1;)") {
+		t.Fatalf("表达式被包进括号，会产生 SyntaxError: %s", script)
+	}
+}
+
+func TestSabaFetch_LockWaitHonorsTimeout(t *testing.T) {
+	bi := NewBrowserInstanceNoMonitor(1, nil, context.Background(), nil)
+	bi.DefaultTimeout = 50 * time.Millisecond
+	release := bi.acquire()
+	defer release()
+
+	start := time.Now()
+	resp := bi.SabaFetch(`fetch("/")`)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("等待操作锁应受 DefaultTimeout 限制，实际等待了 %v", elapsed)
+	}
+	if resp.Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("等待操作锁超时应返回 %q，实际为 %q", context.DeadlineExceeded, resp.Error)
+	}
+	if !bi.Busy() {
+		t.Fatal("超时不应释放其他操作持有的锁")
+	}
+}
@@ -0,0 +1,64 @@
+package browsers
+
+import (
+	"context"
+	"fmt"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+)
+
+// InterceptedIDs 从 HookFunc 收到的事件中取出被暂停请求的 ID
+// requestID 用于 fetch 域的 Continue/Fulfill 等命令，networkID 与 network 域事件中的 RequestID 一致，
+// 可用于把被暂停的请求与之后的响应事件关联起来；事件不是 fetch.EventRequestPaused 时 ok 为 false
+func InterceptedIDs(ev interface{}) (requestID fetch.RequestID, networkID network.RequestID, ok bool) {
+	e, ok := ev.(*fetch.EventRequestPaused)
+	if !ok {
+		return "", "", false
+	}
+	return e.RequestID, e.NetworkID, true
+}
+
+// IsResponseStage 判断被暂停的请求是否处于响应阶段，此时可以读取真实的响应体
+func IsResponseStage(e *fetch.EventRequestPaused) bool {
+	return e.ResponseStatusCode != 0 || e.ResponseErrorReason != ""
+}
+
+// GetResponseBodyForInterception 读取在响应阶段被暂停的请求的响应体，
+// 调用方可以据此决定 FulfillRequest 替换响应或 ContinueRequest 放行
+// 需要启用 BrowserOptions.InterceptResponses，且只能在请求被继续之前调用
+func (bi *BrowserInstance) GetResponseBodyForInterception(requestID fetch.RequestID) ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	var body []byte
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// 返回值已按 base64Encoded 解码
+		var err error
+		body, err = fetch.GetResponseBody(requestID).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("获取拦截的响应体失败: %v", err)
+	}
+	return body, nil
+}
+
+// launchFetchEnable 返回启动时按 HookFunc、ResponseMocks 启用 Fetch 域的命令
+// 只有同时设置了 InterceptResponses 和 HookFunc 时才在响应阶段暂停
+func launchFetchEnable(options BrowserOptions) *fetch.EnableParams {
+	enable := fetch.Enable()
+	if options.InterceptResponses && options.HookFunc != nil {
+		enable = enable.WithPatterns(interceptPatterns())
+	}
+	return enable
+}
+
+// interceptPatterns InterceptResponses 启用时同时在请求阶段和响应阶段暂停所有请求
+func interceptPatterns() []*fetch.RequestPattern {
+	return []*fetch.RequestPattern{
+		{URLPattern: "*", RequestStage: fetch.RequestStageRequest},
+		{URLPattern: "*", RequestStage: fetch.RequestStageResponse},
+	}
+}
@@ -0,0 +1,100 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/luoxk/chromedp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// marshalURLTimeout MarshalJSON 读取当前页面地址的超时，避免卡住的实例拖慢整个导出
+const marshalURLTimeout = 2 * time.Second
+
+// instanceJSON 实例状态快照的 JSON 结构
+type instanceJSON struct {
+	ID          int               `json:"id"`
+	Closed      bool              `json:"closed"`
+	CloseReason string            `json:"closeReason,omitempty"`
+	Busy        bool              `json:"busy"`
+	ParentID    int               `json:"parentId,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	URL         string            `json:"url,omitempty"`
+	Options     optionsJSON       `json:"options"`
+}
+
+// optionsJSON 启动参数摘要，代理和远程地址中的账号密码会被隐去，指纹参数只标记是否设置
+type optionsJSON struct {
+	Path           string `json:"path,omitempty"`
+	RemoteURL      string `json:"remoteUrl,omitempty"`
+	Headless       bool   `json:"headless"`
+	Proxy          string `json:"proxy,omitempty"`
+	UserDir        string `json:"userDir,omitempty"`
+	Fingerprint    bool   `json:"fingerprint"`
+	DefaultTimeout string `json:"defaultTimeout,omitempty"`
+}
+
+// MarshalJSON 导出实例状态的快照，用于监控和管理接口；已关闭的实例不会读取当前页面地址
+func (bi *BrowserInstance) MarshalJSON() ([]byte, error) {
+	reason, closed := bi.ClosedReason()
+	o := bi.options
+	v := instanceJSON{
+		ID:          bi.ID,
+		Closed:      closed,
+		CloseReason: reason,
+		Busy:        bi.Busy(),
+		ParentID:    bi.parentID,
+		Labels:      make(map[string]string, len(bi.Labels)),
+		Options: optionsJSON{
+			Path:        o.Path,
+			RemoteURL:   redactURL(o.RemoteURL),
+			Headless:    headlessFlagEnabled(o),
+			Proxy:       redactURL(o.Proxy),
+			UserDir:     o.UserDir,
+			Fingerprint: o.Fingerprint != "",
+		},
+	}
+	for k, val := range bi.Labels {
+		v.Labels[k] = val
+	}
+	if bi.DefaultTimeout > 0 {
+		v.Options.DefaultTimeout = bi.DefaultTimeout.String()
+	}
+	if !closed && bi.Ctx != nil && chromedp.FromContext(bi.Ctx) != nil {
+		ctx, cancel := context.WithTimeout(bi.Ctx, marshalURLTimeout)
+		var loc string
+		if chromedp.Run(ctx, chromedp.Location(&loc)) == nil {
+			v.URL = loc
+		}
+		cancel()
+	}
+	return json.Marshal(v)
+}
+
+// headlessFlagEnabled 判断实例是否以无头模式启动
+func headlessFlagEnabled(o BrowserOptions) bool {
+	switch o.HeadlessMode {
+	case HeadlessModeOff:
+		return false
+	case HeadlessModeDefault:
+		return o.Headless
+	default:
+		return true
+	}
+}
+
+// redactURL 隐去 URL 中的密码，无法解析时整体隐去
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Redacted()
+	}
+	// host:port 形式的代理没有协议头
+	if u, err := url.Parse("http://" + raw); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Redacted(), "http://")
+	}
+	return "[redacted]"
+}
@@ -0,0 +1,80 @@
+package browsers
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/luoxk/chromedp"
+	"log"
+	"net/http"
+	"sort"
+)
+
+// MockResponse 模拟的响应内容
+type MockResponse struct {
+	Status      int               // 状态码，为 0 时使用 200
+	Headers     map[string]string // 响应头
+	ContentType string            // Content-Type，Headers 中已包含时忽略
+	Body        []byte            // 响应体
+}
+
+// mockHandler 返回一个 fetch 事件监听器，将 URL 匹配 mocks 键的请求直接用模拟响应完成
+// 键的匹配规则与 matchURL 相同，多个键同时匹配时取最长的一个
+// 未匹配的请求和其他事件交给 next（即 HookFunc 的监听器）处理，next 为 nil 时直接放行未匹配的请求
+// 匹配的请求不会再转发给 next，避免 HookFunc 对同一请求调用 ContinueRequest 与模拟响应冲突
+func mockHandler(ctx context.Context, mocks map[string]MockResponse, next func(ev interface{})) func(ev interface{}) {
+	patterns := make([]string, 0, len(mocks))
+	for pattern := range mocks {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return len(patterns[i]) > len(patterns[j])
+	})
+
+	return func(ev interface{}) {
+		e, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			if next != nil {
+				next(ev)
+			}
+			return
+		}
+		for _, pattern := range patterns {
+			if matchURL(pattern, e.Request.URL) {
+				mock := mocks[pattern]
+				go func() {
+					if err := chromedp.Run(ctx, fulfillMock(e.RequestID, mock)); err != nil {
+						log.Printf("Failed to fulfill mocked request %s: %v", e.Request.URL, err)
+					}
+				}()
+				return
+			}
+		}
+		if next != nil {
+			next(ev)
+			return
+		}
+		go chromedp.Run(ctx, fetch.ContinueRequest(e.RequestID))
+	}
+}
+
+func fulfillMock(id fetch.RequestID, mock MockResponse) chromedp.Action {
+	status := mock.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	headers := make([]*fetch.HeaderEntry, 0, len(mock.Headers)+1)
+	hasContentType := false
+	for k, v := range mock.Headers {
+		headers = append(headers, &fetch.HeaderEntry{Name: k, Value: v})
+		if http.CanonicalHeaderKey(k) == "Content-Type" {
+			hasContentType = true
+		}
+	}
+	if !hasContentType && mock.ContentType != "" {
+		headers = append(headers, &fetch.HeaderEntry{Name: "Content-Type", Value: mock.ContentType})
+	}
+	return fetch.FulfillRequest(id, int64(status)).
+		WithResponseHeaders(headers).
+		WithBody(base64.StdEncoding.EncodeToString(mock.Body))
+}
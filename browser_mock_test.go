@@ -0,0 +1,27 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"testing"
+)
+
+func TestMockHandler_MockedURLSkipsHook(t *testing.T) {
+	var hooked []string
+	hook := func(ev interface{}) {
+		if e, ok := ev.(*fetch.EventRequestPaused); ok {
+			hooked = append(hooked, e.Request.URL)
+		}
+	}
+	handler := mockHandler(context.Background(), map[string]MockResponse{
+		"/api/list": {Body: []byte("[]")},
+	}, hook)
+
+	handler(&fetch.EventRequestPaused{RequestID: "1", Request: &network.Request{URL: "https://example.com/api/list?page=1"}})
+	handler(&fetch.EventRequestPaused{RequestID: "2", Request: &network.Request{URL: "https://example.com/index.html"}})
+
+	if len(hooked) != 1 || hooked[0] != "https://example.com/index.html" {
+		t.Fatalf("HookFunc 收到 %v; 期望只有未匹配的请求", hooked)
+	}
+}
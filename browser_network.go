@@ -0,0 +1,205 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// largeResponseBodySize 超过该大小的响应体在设置了 ResponseBodyClient 时直接通过 HTTP 重新获取，
+// 避免 CDP 缓冲区淘汰或截断大响应体
+const largeResponseBodySize = 8 << 20
+
+// InterceptJSON 等待 URL 匹配 urlPattern 的响应完成，读取响应体并反序列化到 out
+// 该方法会阻塞，触发请求的操作（如 Goto、Click）需要在另一个 goroutine 中执行
+func (bi *BrowserInstance) InterceptJSON(urlPattern string, timeout time.Duration, out interface{}) error {
+	body, err := bi.waitResponseBody(urlPattern, timeout)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析响应 JSON 失败: %v", err)
+	}
+	return nil
+}
+
+// WaitForRequest 等待 URL 匹配 urlPattern 的请求即将发出，返回请求信息（含请求头和请求体）
+// 该方法会阻塞，触发请求的操作需要在另一个 goroutine 中执行
+func (bi *BrowserInstance) WaitForRequest(urlPattern string, timeout time.Duration) (*network.Request, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout)
+	defer cancel()
+
+	found := make(chan *network.Request, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if e, ok := ev.(*network.EventRequestWillBeSent); ok && matchURL(urlPattern, e.Request.URL+e.Request.URLFragment) {
+			select {
+			case found <- e.Request:
+			default:
+			}
+		}
+	})
+
+	select {
+	case req := <-found:
+		return req, nil
+	case <-ctx.Done():
+		if bi.Ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: 等待请求 %s", ErrWaitTimeout, urlPattern)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// waitResponseBody 等待匹配的响应加载完成并返回响应体
+func (bi *BrowserInstance) waitResponseBody(urlPattern string, timeout time.Duration) ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout)
+	defer cancel()
+
+	// 响应头到达时记录请求 ID，等 loadingFinished 之后响应体才可读取
+	type finished struct {
+		id   network.RequestID
+		req  *network.Request
+		size float64
+	}
+	done := make(chan finished, 1)
+	requests := make(map[network.RequestID]*network.Request)
+	matched := make(map[network.RequestID]bool)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if matchURL(urlPattern, ev.Request.URL+ev.Request.URLFragment) {
+				requests[ev.RequestID] = ev.Request
+			}
+		case *network.EventResponseReceived:
+			if matchURL(urlPattern, ev.Response.URL) {
+				matched[ev.RequestID] = true
+			}
+		case *network.EventLoadingFinished:
+			if matched[ev.RequestID] {
+				select {
+				case done <- finished{id: ev.RequestID, req: requests[ev.RequestID], size: ev.EncodedDataLength}:
+				default:
+				}
+			}
+		}
+	})
+
+	var f finished
+	select {
+	case f = <-done:
+	case <-ctx.Done():
+		if bi.Ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: 等待响应 %s", ErrWaitTimeout, urlPattern)
+		}
+		return nil, ctx.Err()
+	}
+
+	client := bi.ResponseBodyClient
+	if client != nil && f.req != nil && f.size > largeResponseBodySize {
+		return bi.refetchBody(ctx, client, f.req)
+	}
+
+	var body []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		body, err = network.GetResponseBody(f.id).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		if client != nil && f.req != nil {
+			return bi.refetchBody(ctx, client, f.req)
+		}
+		return nil, fmt.Errorf("获取响应体失败: %v", err)
+	}
+	return body, nil
+}
+
+// refetchBody 携带浏览器的请求头和 cookie，用 client 重新发出请求并返回响应体
+func (bi *BrowserInstance) refetchBody(ctx context.Context, client *http.Client, r *network.Request) ([]byte, error) {
+	req, err := proxiedRequest(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("构造请求失败: %v", err)
+	}
+	// requestWillBeSent 中的请求头不含 cookie，需要从浏览器中取出补上
+	cookie, err := bi.CookieHeader(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("重新请求响应体失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("重新请求响应体失败: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %v", err)
+	}
+	return body, nil
+}
+
+// EnableRequestStats 开始统计实例发出的请求数和失败数，重复调用无效
+func (bi *BrowserInstance) EnableRequestStats() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	if !bi.statsEnabled.CompareAndSwap(false, true) {
+		return nil
+	}
+	chromedp.ListenTarget(bi.Ctx, func(ev interface{}) {
+		switch ev.(type) {
+		case *network.EventRequestWillBeSent:
+			bi.requestsTotal.Add(1)
+		case *network.EventLoadingFailed:
+			bi.requestsFailed.Add(1)
+		}
+	})
+	return nil
+}
+
+// RequestStats 返回 EnableRequestStats 之后发出的请求总数和失败数，重定向的每一跳各计一次
+func (bi *BrowserInstance) RequestStats() (total, failed int64) {
+	return bi.requestsTotal.Load(), bi.requestsFailed.Load()
+}
+
+// matchURL 判断 url 是否匹配 pattern
+// pattern 含 * 时按通配符整体匹配（* 匹配任意字符），否则按子串匹配
+func matchURL(pattern, url string) bool {
+	if !strings.Contains(pattern, "*") {
+		return strings.Contains(url, pattern)
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(url, parts[0]) {
+		return false
+	}
+	url = url[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		i := strings.Index(url, part)
+		if i < 0 {
+			return false
+		}
+		url = url[i+len(part):]
+	}
+	return strings.HasSuffix(url, parts[last])
+}
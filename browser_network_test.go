@@ -0,0 +1,25 @@
+package browsers
+
+import "testing"
+
+func TestMatchURL(t *testing.T) {
+	cases := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"/api/list", "https://example.com/api/list?page=1", true},
+		{"/api/list", "https://example.com/api/detail", false},
+		{"https://example.com/*", "https://example.com/a/b", true},
+		{"*/api/*/items", "https://example.com/api/v2/items", true},
+		{"*/api/*/items", "https://example.com/api/v2/items?x=1", false},
+		{"*.json", "https://cdn.example.com/data.json", true},
+		{"a*b*b", "ab", false},
+		{"*", "anything", true},
+	}
+	for _, c := range cases {
+		if got := matchURL(c.pattern, c.url); got != c.want {
+			t.Errorf("matchURL(%q, %q) = %v; 期望 %v", c.pattern, c.url, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/luoxk/chromedp"
+)
+
+// permissionDescriptors PermissionType 到 Permissions API 描述符名称的映射，未列出的直接使用类型名
+var permissionDescriptors = map[browser.PermissionType]browser.PermissionDescriptor{
+	browser.PermissionTypeAudioCapture:            {Name: "microphone"},
+	browser.PermissionTypeVideoCapture:            {Name: "camera"},
+	browser.PermissionTypeCameraPanTiltZoom:       {Name: "camera", PanTiltZoom: true},
+	browser.PermissionTypeClipboardReadWrite:      {Name: "clipboard-read"},
+	browser.PermissionTypeClipboardSanitizedWrite: {Name: "clipboard-write"},
+	browser.PermissionTypeMidiSysex:               {Name: "midi", Sysex: true},
+	browser.PermissionTypeDurableStorage:          {Name: "persistent-storage"},
+	browser.PermissionTypeBackgroundSync:          {Name: "background-sync"},
+	browser.PermissionTypePeriodicBackgroundSync:  {Name: "periodic-background-sync"},
+	browser.PermissionTypeDisplayCapture:          {Name: "display-capture"},
+	browser.PermissionTypeIdleDetection:           {Name: "idle-detection"},
+	browser.PermissionTypeLocalFonts:              {Name: "local-fonts"},
+	browser.PermissionTypePaymentHandler:          {Name: "payment-handler"},
+	browser.PermissionTypeStorageAccess:           {Name: "storage-access"},
+	browser.PermissionTypeWakeLockScreen:          {Name: "screen-wake-lock"},
+	browser.PermissionTypeWakeLockSystem:          {Name: "system-wake-lock"},
+	browser.PermissionTypeWindowManagement:        {Name: "window-management"},
+}
+
+// HandlePermission 预先允许或拒绝 origin 的某项权限，避免页面弹出的权限请求阻塞自动化流程
+// origin 为空时作用于所有来源；ResetPermissions 可以恢复默认
+func (bi *BrowserInstance) HandlePermission(origin string, permission browser.PermissionType, grant bool) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// 权限属于浏览器级命令，需要使用 Browser 执行器，并限定在当前实例的浏览器上下文中
+		c := chromedp.FromContext(ctx)
+		bctx := cdp.WithExecutor(ctx, c.Browser)
+
+		if grant {
+			p := browser.GrantPermissions([]browser.PermissionType{permission})
+			if origin != "" {
+				p = p.WithOrigin(origin)
+			}
+			if c.BrowserContextID != "" {
+				p = p.WithBrowserContextID(c.BrowserContextID)
+			}
+			return p.Do(bctx)
+		}
+
+		desc, ok := permissionDescriptors[permission]
+		if !ok {
+			desc = browser.PermissionDescriptor{Name: string(permission)}
+		}
+		p := browser.SetPermission(&desc, browser.PermissionSettingDenied)
+		if origin != "" {
+			p = p.WithOrigin(origin)
+		}
+		if c.BrowserContextID != "" {
+			p = p.WithBrowserContextID(c.BrowserContextID)
+		}
+		return p.Do(bctx)
+	}))
+}
+
+// ResetPermissions 清除 HandlePermission 设置的所有权限
+func (bi *BrowserInstance) ResetPermissions() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+	return chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		c := chromedp.FromContext(ctx)
+		p := browser.ResetPermissions()
+		if c.BrowserContextID != "" {
+			p = p.WithBrowserContextID(c.BrowserContextID)
+		}
+		return p.Do(cdp.WithExecutor(ctx, c.Browser))
+	}))
+}
@@ -0,0 +1,243 @@
+package browsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// defaultWarmupConcurrency Warmup 默认的并行启动数量
+const defaultWarmupConcurrency = 4
+
+// defaultMapConcurrency MaxSize 为 0 时 Map 的并行任务数量
+const defaultMapConcurrency = 4
+
+// BrowserPool 复用浏览器实例的池，按需启动，用完归还
+type BrowserPool struct {
+	controller *BrowserController
+	options    BrowserOptions
+
+	MaxSize           int // 池中实例数量上限（空闲 + 使用中 + 启动中），为 0 时不限制
+	WarmupConcurrency int // Warmup 的并行启动数量，为 0 时使用默认值
+
+	mu        sync.Mutex
+	idle      []*BrowserInstance       // 空闲实例
+	inUse     map[int]*BrowserInstance // 已借出的实例
+	launching int                      // 正在启动的实例数量
+	changed   chan struct{}            // 池状态变化时关闭，用于唤醒等待者
+	closed    bool
+}
+
+// NewBrowserPool 创建一个浏览器池，按需启动的实例使用 options 作为启动参数
+func NewBrowserPool(controller *BrowserController, maxSize int, options BrowserOptions) *BrowserPool {
+	return &BrowserPool{
+		controller: controller,
+		options:    options,
+		MaxSize:    maxSize,
+		inUse:      make(map[int]*BrowserInstance),
+		changed:    make(chan struct{}),
+	}
+}
+
+// Acquire 借出一个实例，优先使用空闲实例，不足时启动新实例，达到上限时等待归还
+func (p *BrowserPool) Acquire(ctx context.Context) (*BrowserInstance, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("browser pool is closed")
+		}
+		// 跳过已经关闭（崩溃）的空闲实例
+		for len(p.idle) > 0 {
+			inst := p.idle[len(p.idle)-1]
+			p.idle = p.idle[:len(p.idle)-1]
+			if !inst.Closed() {
+				p.inUse[inst.ID] = inst
+				p.mu.Unlock()
+				return inst, nil
+			}
+		}
+		if p.MaxSize <= 0 || len(p.inUse)+p.launching < p.MaxSize {
+			p.launching++
+			p.mu.Unlock()
+
+			inst, err := p.controller.LaunchBrowser(p.options)
+
+			p.mu.Lock()
+			p.launching--
+			if err == nil {
+				inst.pool.Store(p)
+				p.inUse[inst.ID] = inst
+			}
+			p.notifyLocked()
+			p.mu.Unlock()
+			return inst, err
+		}
+		wait := p.changed
+		p.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Release 归还实例，已关闭的实例直接丢弃，池关闭后归还的实例会被关闭
+func (p *BrowserPool) Release(inst *BrowserInstance) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.inUse, inst.ID)
+	switch {
+	case p.closed:
+		p.controller.CloseBrowser(inst.ID)
+	case !inst.Closed():
+		p.idle = append(p.idle, inst)
+	}
+	p.notifyLocked()
+}
+
+// Warmup 并行启动 n 个实例放入空闲队列，使池在流量到达前就绪
+// 受 MaxSize 限制时只启动剩余的名额；返回所有启动失败的错误
+func (p *BrowserPool) Warmup(n int, options BrowserOptions) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return fmt.Errorf("browser pool is closed")
+	}
+	if p.MaxSize > 0 {
+		if free := p.MaxSize - len(p.idle) - len(p.inUse) - p.launching; n > free {
+			n = free
+		}
+	}
+	if n <= 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	p.launching += n
+	concurrency := p.WarmupConcurrency
+	p.mu.Unlock()
+
+	if concurrency <= 0 {
+		concurrency = defaultWarmupConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			inst, err := p.controller.LaunchBrowser(options)
+
+			p.mu.Lock()
+			p.launching--
+			if err != nil {
+				errs[i] = err
+			} else if p.closed {
+				p.controller.CloseBrowser(inst.ID)
+			} else {
+				inst.pool.Store(p)
+				p.idle = append(p.idle, inst)
+			}
+			p.notifyLocked()
+			p.mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close 关闭池和所有空闲实例，使用中的实例在归还时关闭
+func (p *BrowserPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for _, inst := range p.idle {
+		p.controller.CloseBrowser(inst.ID)
+	}
+	p.idle = nil
+	p.notifyLocked()
+}
+
+// takeIdle 内存回收前从空闲队列取出 inst，并把它的名额记为启动中，inst 不在空闲队列时返回 false
+func (p *BrowserPool) takeIdle(inst *BrowserInstance) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, idle := range p.idle {
+		if idle == inst {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			p.launching++
+			return true
+		}
+	}
+	return false
+}
+
+// putRecycled 归还 takeIdle 占用的名额，inst 为替代实例或未回收的原实例，err 不为 nil 时只释放名额
+func (p *BrowserPool) putRecycled(inst *BrowserInstance, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.launching--
+	switch {
+	case err != nil || inst == nil:
+	case p.closed:
+		p.controller.CloseBrowser(inst.ID)
+	default:
+		inst.pool.Store(p)
+		p.idle = append(p.idle, inst)
+	}
+	p.notifyLocked()
+}
+
+// notifyLocked 唤醒所有等待者，调用时需持有 p.mu
+func (p *BrowserPool) notifyLocked() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// Map 将 urls 分发给池中的实例并发执行 fn，每个任务前后自动 Acquire/Release
+// 并发数为 MaxSize，未设置上限时为 defaultMapConcurrency；返回的错误与 urls 按位置对应，成功的位置为 nil
+func (p *BrowserPool) Map(urls []string, fn func(inst *BrowserInstance, url string) error) []error {
+	workers := p.MaxSize
+	if workers <= 0 {
+		workers = defaultMapConcurrency
+	}
+	if workers > len(urls) {
+		workers = len(urls)
+	}
+
+	errs := make([]error, len(urls))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				inst, err := p.Acquire(context.Background())
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(inst, urls[i])
+				p.Release(inst)
+			}
+		}()
+	}
+	for i := range urls {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,33 @@
+package browsers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBrowserPool_RecycleIdle(t *testing.T) {
+	p := NewBrowserPool(NewBrowserController(), 1, BrowserOptions{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	old := NewBrowserInstanceNoMonitor(1, nil, ctx, cancel)
+	replacement := NewBrowserInstanceNoMonitor(2, nil, ctx, cancel)
+	p.idle = append(p.idle, old)
+
+	if !p.takeIdle(old) {
+		t.Fatal("空闲实例应能被取出回收")
+	}
+	if len(p.idle) != 0 || p.launching != 1 {
+		t.Fatalf("回收期间应占用名额: idle=%d launching=%d", len(p.idle), p.launching)
+	}
+	if p.takeIdle(old) {
+		t.Fatal("不在空闲队列中的实例不应被回收")
+	}
+
+	p.putRecycled(replacement, nil)
+	if len(p.idle) != 1 || p.idle[0] != replacement || p.launching != 0 {
+		t.Fatalf("替代实例应放回空闲队列: idle=%v launching=%d", p.idle, p.launching)
+	}
+	if replacement.pool.Load() != p {
+		t.Fatal("替代实例应归属于原来的池")
+	}
+}
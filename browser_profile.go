@@ -0,0 +1,66 @@
+package browsers
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cloneUserDir 将用户目录复制到一个新的临时目录，返回临时目录路径
+func cloneUserDir(src string) (string, error) {
+	dst, err := os.MkdirTemp("", "browsers-profile-*")
+	if err != nil {
+		return "", err
+	}
+	if err = copyDir(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+	return dst, nil
+}
+
+// copyDir 递归复制目录，跳过 Chrome 的 Singleton* 锁文件
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if strings.HasPrefix(d.Name(), "Singleton") {
+			return nil
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o700)
+		}
+		// 锁文件以外的符号链接、套接字等不需要复制
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,162 @@
+package browsers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// proxyRequestTimeout 经运行时代理转发的单个请求的超时
+const proxyRequestTimeout = 60 * time.Second
+
+// SetProxy 在不重启浏览器的情况下切换代理，proxy 为空时恢复浏览器自身的网络栈
+//
+// CDP 无法修改启动时的代理配置，这里通过 Fetch 域拦截所有 http/https 请求，
+// 由 Go 端经新代理发出后再用 FulfillRequest 回填响应。
+// 启用后与 HookFunc、ResponseMocks 同时拦截请求会产生冲突，不要混用。
+func (bi *BrowserInstance) SetProxy(proxy string) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	bi.proxyMu.Lock()
+	defer bi.proxyMu.Unlock()
+
+	if proxy == "" {
+		if bi.proxyCancel == nil {
+			return nil
+		}
+		bi.proxyCancel()
+		bi.proxyCancel = nil
+		if bi.fetchAtLaunch {
+			// 恢复启动时的拦截模式，否则 HookFunc 会继续收到代理期间才拦截的请求
+			return chromedp.Run(bi.Ctx, launchFetchEnable(bi.options))
+		}
+		return chromedp.Run(bi.Ctx, fetch.Disable())
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		return fmt.Errorf("代理地址不合法: %s", proxy)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+		Timeout:   proxyRequestTimeout,
+		// 重定向交给浏览器处理，保证地址栏和 cookie 行为一致
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	if bi.proxyCancel != nil {
+		bi.proxyCancel()
+	}
+	lctx, cancel := context.WithCancel(bi.Ctx)
+	chromedp.ListenTarget(lctx, func(ev interface{}) {
+		// 响应阶段的暂停来自启动时的 InterceptResponses，交给 HookFunc 处理
+		if e, ok := ev.(*fetch.EventRequestPaused); ok && e.ResponseStatusCode == 0 && e.ResponseErrorReason == "" {
+			go bi.proxyRequest(lctx, client, e)
+		}
+	})
+	// Fetch.enable 会替换之前的拦截模式，需要保留启动时在响应阶段的拦截
+	patterns := []*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageRequest}}
+	if bi.fetchAtLaunch && bi.options.InterceptResponses && bi.options.HookFunc != nil {
+		// 已包含请求阶段的 "*"
+		patterns = interceptPatterns()
+	}
+	err = chromedp.Run(bi.Ctx, fetch.Enable().WithPatterns(patterns))
+	if err != nil {
+		cancel()
+		return err
+	}
+	bi.proxyCancel = cancel
+	return nil
+}
+
+// proxyRequest 通过 client 发出被暂停的请求并回填响应
+func (bi *BrowserInstance) proxyRequest(ctx context.Context, client *http.Client, e *fetch.EventRequestPaused) {
+	action := chromedp.ActionFunc(func(ctx context.Context) error {
+		// data:、blob: 等非网络请求直接放行
+		if !strings.HasPrefix(e.Request.URL, "http://") && !strings.HasPrefix(e.Request.URL, "https://") {
+			return fetch.ContinueRequest(e.RequestID).Do(ctx)
+		}
+
+		req, err := proxiedRequest(ctx, e.Request)
+		if err != nil {
+			return fetch.FailRequest(e.RequestID, network.ErrorReasonFailed).Do(ctx)
+		}
+		// Fetch 请求阶段的请求头不含 cookie，需要从浏览器中取出补上
+		cookies, err := network.GetCookies().WithURLs([]string{e.Request.URL}).Do(ctx)
+		if err == nil && len(cookies) > 0 {
+			pairs := make([]string, 0, len(cookies))
+			for _, c := range cookies {
+				pairs = append(pairs, c.Name+"="+c.Value)
+			}
+			req.Header.Set("Cookie", strings.Join(pairs, "; "))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fetch.FailRequest(e.RequestID, network.ErrorReasonConnectionFailed).Do(ctx)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fetch.FailRequest(e.RequestID, network.ErrorReasonConnectionFailed).Do(ctx)
+		}
+
+		headers := make([]*fetch.HeaderEntry, 0, len(resp.Header))
+		for name, values := range resp.Header {
+			// 响应体已被解压，长度和编码头不再准确
+			if name == "Content-Encoding" || name == "Content-Length" {
+				continue
+			}
+			for _, v := range values {
+				headers = append(headers, &fetch.HeaderEntry{Name: name, Value: v})
+			}
+		}
+		return fetch.FulfillRequest(e.RequestID, int64(resp.StatusCode)).
+			WithResponseHeaders(headers).
+			WithBody(base64.StdEncoding.EncodeToString(body)).
+			Do(ctx)
+	})
+	if err := chromedp.Run(ctx, action); err != nil && ctx.Err() == nil {
+		log.Printf("Failed to proxy request %s for browser instance %d: %v", e.Request.URL, bi.ID, err)
+	}
+}
+
+// proxiedRequest 根据浏览器的请求构造 http.Request
+func proxiedRequest(ctx context.Context, r *network.Request) (*http.Request, error) {
+	var body io.Reader
+	if len(r.PostDataEntries) > 0 {
+		var buf bytes.Buffer
+		for _, entry := range r.PostDataEntries {
+			b, err := base64.StdEncoding.DecodeString(entry.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(b)
+		}
+		body = &buf
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL+r.URLFragment, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.Headers {
+		req.Header.Set(name, fmt.Sprint(value))
+	}
+	// 交给 Transport 自动处理 gzip，回填给浏览器的是解压后的内容
+	req.Header.Del("Accept-Encoding")
+	return req, nil
+}
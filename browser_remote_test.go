@@ -0,0 +1,293 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/chromedp/cdproto/page"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/luoxk/chromedp"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDevTools 一个只应答命令的 DevTools 服务端，记录收到的方法，用于不启动 Chrome 测试实例的 CDP 调用
+type fakeDevTools struct {
+	mu      sync.Mutex
+	next    int
+	methods []string // 收到的方法，带参数的记录为 "方法 参数"
+}
+
+func (f *fakeDevTools) record(method string) {
+	f.mu.Lock()
+	f.methods = append(f.methods, method)
+	f.mu.Unlock()
+}
+
+func (f *fakeDevTools) received(method string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeDevTools) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, m := range f.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeDevTools) newID(prefix string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	return fmt.Sprintf("%s%d", prefix, f.next)
+}
+
+func (f *fakeDevTools) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		data, err := wsutil.ReadClientText(conn)
+		if err != nil {
+			return
+		}
+		var msg struct {
+			ID        int64           `json:"id"`
+			SessionID string          `json:"sessionId,omitempty"`
+			Method    string          `json:"method"`
+			Params    json.RawMessage `json:"params"`
+		}
+		if err = json.Unmarshal(data, &msg); err != nil {
+			return
+		}
+
+		result, record, event := `{}`, msg.Method, ""
+		switch msg.Method {
+		case "Target.createBrowserContext":
+			result = fmt.Sprintf(`{"browserContextId":%q}`, f.newID("ctx"))
+		case "Target.createTarget":
+			result = fmt.Sprintf(`{"targetId":%q}`, f.newID("target"))
+		case "Target.attachToTarget":
+			result = fmt.Sprintf(`{"sessionId":%q}`, f.newID("session"))
+		case "Target.closeTarget":
+			result = `{"success":true}`
+		case "Runtime.evaluate":
+			result = `{"result":{"type":"object","className":"Window"}}`
+		case "Page.navigate":
+			var p struct {
+				Referrer string `json:"referrer"`
+			}
+			json.Unmarshal(msg.Params, &p)
+			record = msg.Method + " " + p.Referrer
+			// 不返回 loaderId 并推送同文档导航事件，让导航立即完成
+			result = `{"frameId":"frame"}`
+			event = fmt.Sprintf(`{"method":"Page.navigatedWithinDocument","sessionId":%q,"params":{"frameId":"frame","url":"about:blank"}}`, msg.SessionID)
+		case "Fetch.enable":
+			record = msg.Method + " " + string(msg.Params)
+		case "Page.crash":
+			event = fmt.Sprintf(`{"method":"Inspector.targetCrashed","sessionId":%q,"params":{}}`, msg.SessionID)
+		case "Target.disposeBrowserContext":
+			var p struct {
+				BrowserContextID string `json:"browserContextId"`
+			}
+			json.Unmarshal(msg.Params, &p)
+			record = msg.Method + " " + p.BrowserContextID
+		}
+		f.record(record)
+
+		reply := fmt.Sprintf(`{"id":%d,"result":%s}`, msg.ID, result)
+		if msg.SessionID != "" {
+			reply = fmt.Sprintf(`{"id":%d,"sessionId":%q,"result":%s}`, msg.ID, msg.SessionID, result)
+		}
+		if err = wsutil.WriteServerText(conn, []byte(reply)); err != nil {
+			return
+		}
+		if event != "" {
+			if err = wsutil.WriteServerText(conn, []byte(event)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestBrowserController_CloseRemoteKeepsOthers(t *testing.T) {
+	devtools := &fakeDevTools{}
+	srv := httptest.NewServer(devtools)
+	defer srv.Close()
+	remoteURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/browser/fake"
+
+	controller := NewBrowserController()
+	options := BrowserOptions{RemoteURL: remoteURL, SkipInitialNavigate: true}
+	a, err := controller.LaunchBrowser(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := controller.LaunchBrowser(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctxA := chromedp.FromContext(a.Ctx).BrowserContextID
+	ctxB := chromedp.FromContext(b.Ctx).BrowserContextID
+	if ctxA == "" || ctxA == ctxB {
+		t.Fatalf("远程实例应使用各自的浏览器上下文: %q %q", ctxA, ctxB)
+	}
+
+	if err = controller.CloseBrowser(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && !devtools.received("Target.disposeBrowserContext "+string(ctxA)) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !devtools.received("Target.disposeBrowserContext " + string(ctxA)) {
+		t.Error("关闭远程实例应销毁它自己的浏览器上下文")
+	}
+	if devtools.received("Browser.close") {
+		t.Fatal("关闭远程实例不应关闭共享的远程浏览器")
+	}
+	if devtools.received("Target.disposeBrowserContext " + string(ctxB)) {
+		t.Fatal("关闭一个远程实例不应销毁其他实例的浏览器上下文")
+	}
+
+	if b.Closed() {
+		t.Fatal("另一个远程实例不应被关闭")
+	}
+	if err = chromedp.Run(b.Ctx, page.StopLoading()); err != nil {
+		t.Fatalf("另一个远程实例应仍然可用: %v", err)
+	}
+	controller.CloseBrowser(b.ID)
+}
+
+func TestNewIncognitoTab_InheritsReferer(t *testing.T) {
+	devtools := &fakeDevTools{}
+	srv := httptest.NewServer(devtools)
+	defer srv.Close()
+	remoteURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/browser/fake"
+
+	controller := NewBrowserController()
+	parent, err := controller.LaunchBrowser(BrowserOptions{
+		RemoteURL:           remoteURL,
+		SkipInitialNavigate: true,
+		Referer:             "https://referer.example/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.CloseAllBrowsers()
+
+	child, err := parent.NewIncognitoTab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer child.Close()
+	if err = child.Goto("https://example.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if !devtools.received("Page.navigate https://referer.example/") {
+		t.Fatal("子实例的 Goto 应携带父实例的 Referer")
+	}
+}
+
+func TestWatchCrash_RunsHandlersThenCloses(t *testing.T) {
+	devtools := &fakeDevTools{}
+	srv := httptest.NewServer(devtools)
+	defer srv.Close()
+	remoteURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/browser/fake"
+
+	controller := NewBrowserController()
+	instance, err := controller.LaunchBrowser(BrowserOptions{RemoteURL: remoteURL, SkipInitialNavigate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.CloseAllBrowsers()
+
+	var calls []string
+	done := make(chan struct{})
+	instance.OnCrash(func() {
+		if instance.Closed() {
+			t.Error("handler 返回之前实例不应被关闭")
+		}
+		calls = append(calls, "first")
+	})
+	instance.OnCrash(func() {
+		calls = append(calls, "second")
+		close(done)
+	})
+	if err = chromedp.Run(instance.Ctx, page.Crash()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("崩溃后应调用 OnCrash 注册的 handler")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && !instance.Closed() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reason, ok := instance.ClosedReason(); !ok || reason != string(CloseReasonCrashed) {
+		t.Fatalf("崩溃后实例应以 %q 关闭，实际为 %q, %v", CloseReasonCrashed, reason, ok)
+	}
+	if len(calls) != 2 || calls[0] != "first" {
+		t.Fatalf("handler 应按注册顺序依次调用，实际为 %v", calls)
+	}
+}
+
+func TestSetProxy_KeepsLaunchInterceptPatterns(t *testing.T) {
+	devtools := &fakeDevTools{}
+	srv := httptest.NewServer(devtools)
+	defer srv.Close()
+	remoteURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/browser/fake"
+
+	controller := NewBrowserController()
+	instance, err := controller.LaunchBrowser(BrowserOptions{
+		RemoteURL:           remoteURL,
+		SkipInitialNavigate: true,
+		InterceptResponses:  true,
+		HookFunc: func(ctx context.Context) func(ev interface{}) {
+			return func(ev interface{}) {}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.CloseAllBrowsers()
+
+	launch := `Fetch.enable {"patterns":[{"urlPattern":"*","requestStage":"Request"},{"urlPattern":"*","requestStage":"Response"}]}`
+	if devtools.count(launch) != 1 {
+		t.Fatalf("启动时应在请求和响应阶段拦截，实际收到 %v", devtools.methods)
+	}
+	if err = instance.SetProxy("http://127.0.0.1:1"); err != nil {
+		t.Fatal(err)
+	}
+	if devtools.count(launch) != 2 {
+		t.Fatal("启用代理时应保留启动时响应阶段的拦截")
+	}
+	if err = instance.SetProxy(""); err != nil {
+		t.Fatal(err)
+	}
+	if devtools.count(launch) != 3 || devtools.received("Fetch.disable") {
+		t.Fatal("关闭代理时应恢复启动时的拦截模式")
+	}
+}
@@ -0,0 +1,44 @@
+package browsers
+
+import (
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/luoxk/chromedp"
+	"strings"
+)
+
+// clearPageStorageJS 清空当前页面的 localStorage 和 sessionStorage，返回页面的 origin
+// about:blank 等页面访问存储会抛出异常，忽略即可
+const clearPageStorageJS = `(function() {
+	try { localStorage.clear(); } catch (e) {}
+	try { sessionStorage.clear(); } catch (e) {}
+	return location.origin;
+})()`
+
+// Reset 将实例恢复到接近刚启动的状态，用于池化复用而不必重启 Chrome：
+// 清空当前页面的 localStorage、sessionStorage 和其他站点数据，清除 cookie、缓存和模拟设置，最后导航到 about:blank
+// 每一步都会执行，返回遇到的第一个错误
+func (bi *BrowserInstance) Reset() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	var first error
+	record := func(err error) {
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+
+	// 存储属于当前 origin，需要在离开页面之前清理
+	var origin string
+	record(bi.callJs(clearPageStorageJS, &origin))
+	if strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://") {
+		record(chromedp.Run(bi.Ctx, storage.ClearDataForOrigin(origin, "all")))
+	}
+	record(chromedp.Run(bi.Ctx, network.ClearBrowserCookies()))
+	record(chromedp.Run(bi.Ctx, network.ClearBrowserCache()))
+	record(bi.ClearEmulationOverrides())
+	record(chromedp.Run(bi.Ctx, chromedp.Navigate("about:blank")))
+	return first
+}
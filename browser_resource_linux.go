@@ -0,0 +1,86 @@
+package browsers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks /proc/<pid>/stat 中 CPU 时间的单位，Linux 上几乎总是 100
+const clockTicks = 100
+
+// procStat /proc/<pid>/stat 中需要的字段
+type procStat struct {
+	ppid  int
+	utime uint64
+	stime uint64
+	rss   uint64 // 页数
+}
+
+// processTreeStats 通过 /proc 统计 pid 及其所有后代进程的资源占用
+func processTreeStats(pid int) (*ProcessStats, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[int]procStat)
+	children := make(map[int][]int)
+	for _, e := range entries {
+		p, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		st, err := readProcStat(p)
+		if err != nil {
+			// 进程可能在遍历过程中退出
+			continue
+		}
+		stats[p] = st
+		children[st.ppid] = append(children[st.ppid], p)
+	}
+	if _, ok := stats[pid]; !ok {
+		return nil, fmt.Errorf("进程 %d 不存在", pid)
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	result := &ProcessStats{PID: pid}
+	queue := []int{pid}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		st := stats[p]
+		result.Processes++
+		result.RSS += st.rss * pageSize
+		result.CPUTime += time.Duration(st.utime+st.stime) * time.Second / clockTicks
+		queue = append(queue, children[p]...)
+	}
+	return result, nil
+}
+
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	// 第二个字段是括号包裹的进程名，可能含空格，从最后一个 ')' 之后开始解析
+	i := bytes.LastIndexByte(data, ')')
+	if i < 0 {
+		return procStat{}, fmt.Errorf("无法解析 /proc/%d/stat", pid)
+	}
+	// 从 state 字段开始，ppid 为第 4 个字段，utime/stime 为第 14/15 个，rss 为第 24 个
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("无法解析 /proc/%d/stat", pid)
+	}
+	var st procStat
+	st.ppid, _ = strconv.Atoi(fields[1])
+	st.utime, _ = strconv.ParseUint(fields[11], 10, 64)
+	st.stime, _ = strconv.ParseUint(fields[12], 10, 64)
+	st.rss, _ = strconv.ParseUint(fields[21], 10, 64)
+	return st, nil
+}
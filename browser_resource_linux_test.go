@@ -0,0 +1,16 @@
+package browsers
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessTreeStats(t *testing.T) {
+	stats, err := processTreeStats(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.PID != os.Getpid() || stats.Processes < 1 || stats.RSS == 0 {
+		t.Errorf("统计结果不合理: %+v", stats)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package browsers
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// processTreeStats 目前只支持通过 /proc 统计，其他系统返回错误
+func processTreeStats(pid int) (*ProcessStats, error) {
+	return nil, fmt.Errorf("当前系统 %s 不支持统计资源占用", runtime.GOOS)
+}
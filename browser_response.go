@@ -0,0 +1,107 @@
+package browsers
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"sync"
+)
+
+// responseLRUSize 每个实例记录的最近响应数量
+const responseLRUSize = 200
+
+// responseLRU 按 URL 记录最近加载完成的响应的请求 ID，同一 URL 只保留最新一次
+type responseLRU struct {
+	mu      sync.Mutex
+	ll      *list.List                   // 元素为 responseEntry，最近的在前
+	index   map[string]*list.Element     // URL 到链表元素
+	pending map[network.RequestID]string // 已收到响应头、尚未加载完成的请求
+}
+
+type responseEntry struct {
+	url string
+	id  network.RequestID
+}
+
+func newResponseLRU() *responseLRU {
+	return &responseLRU{
+		ll:      list.New(),
+		index:   make(map[string]*list.Element),
+		pending: make(map[network.RequestID]string),
+	}
+}
+
+func (c *responseLRU) handle(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventResponseReceived:
+		c.pending[ev.RequestID] = ev.Response.URL
+	case *network.EventLoadingFinished:
+		if url, ok := c.pending[ev.RequestID]; ok {
+			delete(c.pending, ev.RequestID)
+			c.add(url, ev.RequestID)
+		}
+	case *network.EventLoadingFailed:
+		delete(c.pending, ev.RequestID)
+	}
+}
+
+// add 记录 url 最新的请求 ID，超出容量时淘汰最久未加载的 URL，调用时需持有 mu
+func (c *responseLRU) add(url string, id network.RequestID) {
+	if el, ok := c.index[url]; ok {
+		el.Value = responseEntry{url: url, id: id}
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.index[url] = c.ll.PushFront(responseEntry{url: url, id: id})
+	if c.ll.Len() > responseLRUSize {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.index, last.Value.(responseEntry).url)
+	}
+}
+
+// lookup 返回最近一次 URL 匹配 urlPattern 的响应
+func (c *responseLRU) lookup(urlPattern string) (network.RequestID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(responseEntry); matchURL(urlPattern, e.url) {
+			return e.id, true
+		}
+	}
+	return "", false
+}
+
+// trackResponses 开始记录加载完成的响应，启动实例时调用
+func (bi *BrowserInstance) trackResponses() {
+	chromedp.ListenTarget(bi.Ctx, bi.responses.handle)
+}
+
+// ResponseBody 返回最近一次 URL 匹配 urlPattern 的已完成响应的响应体，匹配规则与 InterceptJSON 相同
+// 与 InterceptJSON 不同，它不等待新的响应；只记录最近 responseLRUSize 个 URL，
+// 浏览器也可能已经释放了较早的响应体，此时返回错误
+func (bi *BrowserInstance) ResponseBody(urlPattern string) ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+	id, ok := bi.responses.lookup(urlPattern)
+	if !ok {
+		return nil, fmt.Errorf("没有 URL 匹配 %s 的响应", urlPattern)
+	}
+
+	var body []byte
+	err := chromedp.Run(bi.Ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		body, err = network.GetResponseBody(id).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("获取响应体失败: %v", err)
+	}
+	return body, nil
+}
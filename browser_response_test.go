@@ -0,0 +1,43 @@
+package browsers
+
+import (
+	"fmt"
+	"github.com/chromedp/cdproto/network"
+	"testing"
+)
+
+func TestResponseLRU(t *testing.T) {
+	c := newResponseLRU()
+	finish := func(id network.RequestID, url string) {
+		c.handle(&network.EventResponseReceived{RequestID: id, Response: &network.Response{URL: url}})
+		c.handle(&network.EventLoadingFinished{RequestID: id})
+	}
+
+	finish("1", "https://example.com/api/user")
+	finish("2", "https://example.com/api/list")
+	finish("3", "https://example.com/api/user")
+	if id, ok := c.lookup("/api/user"); !ok || id != "3" {
+		t.Fatalf("应返回同一 URL 最新的请求，实际为 %q, %v", id, ok)
+	}
+	if id, ok := c.lookup("https://example.com/api/*"); !ok || id != "3" {
+		t.Fatalf("应返回最近匹配的请求，实际为 %q, %v", id, ok)
+	}
+
+	// 未完成或失败的请求不记录
+	c.handle(&network.EventResponseReceived{RequestID: "4", Response: &network.Response{URL: "https://example.com/failed"}})
+	c.handle(&network.EventLoadingFailed{RequestID: "4"})
+	if _, ok := c.lookup("/failed"); ok {
+		t.Fatal("失败的请求不应被记录")
+	}
+
+	// 超出容量时淘汰最早的 URL
+	for i := 0; i < responseLRUSize; i++ {
+		finish(network.RequestID(fmt.Sprint(100+i)), fmt.Sprintf("https://example.com/page/%d", i))
+	}
+	if _, ok := c.lookup("/api/"); ok {
+		t.Fatal("超出容量后最早的 URL 应被淘汰")
+	}
+	if c.ll.Len() != responseLRUSize || len(c.index) != responseLRUSize {
+		t.Fatalf("记录数量应为 %d，实际为 %d/%d", responseLRUSize, c.ll.Len(), len(c.index))
+	}
+}
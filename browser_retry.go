@@ -0,0 +1,55 @@
+package browsers
+
+import (
+	"context"
+	"fmt"
+	"github.com/luoxk/chromedp"
+	"time"
+)
+
+// maxRetryBackoff Retry 两次执行之间的最长等待时间
+const maxRetryBackoff = 30 * time.Second
+
+// Retry 执行 action，失败后等待 backoff 重试，每次重试的等待时间翻倍但不超过 maxRetryBackoff，最多执行 attempts 次
+// action 收到的 ctx 可直接用于 chromedp 动作；浏览器关闭时立即停止并返回最后一次错误
+func (bi *BrowserInstance) Retry(attempts int, backoff time.Duration, action func(ctx context.Context) error) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	var err error
+	var tried int
+	for i := 0; i < attempts; i++ {
+		if bi.Closed() {
+			break
+		}
+		tried++
+		if err = chromedp.Run(bi.Ctx, chromedp.ActionFunc(action)); err == nil {
+			return nil
+		}
+		if i == attempts-1 {
+			break
+		}
+
+		timer := time.NewTimer(retryBackoff(backoff, i))
+		select {
+		case <-bi.Ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("重试被取消: %w", err)
+		case <-timer.C:
+		}
+	}
+	if err == nil {
+		return ErrBrowserClosed
+	}
+	return fmt.Errorf("执行 %d 次后仍失败: %w", tried, err)
+}
+
+// retryBackoff 返回第 i 次失败后的等待时间 backoff<<i，上限为 maxRetryBackoff
+// 先判断再移位，避免 attempts 很大时移位溢出成负数或 0
+func retryBackoff(backoff time.Duration, i int) time.Duration {
+	if i >= 63 || backoff > maxRetryBackoff>>i {
+		return maxRetryBackoff
+	}
+	return min(backoff<<i, maxRetryBackoff)
+}
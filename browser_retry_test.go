@@ -0,0 +1,25 @@
+package browsers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		backoff time.Duration
+		i       int
+		want    time.Duration
+	}{
+		{100 * time.Millisecond, 0, 100 * time.Millisecond},
+		{100 * time.Millisecond, 3, 800 * time.Millisecond},
+		{100 * time.Millisecond, 20, maxRetryBackoff},
+		{time.Second, 62, maxRetryBackoff},
+		{time.Second, 100, maxRetryBackoff},
+	}
+	for _, c := range cases {
+		if got := retryBackoff(c.backoff, c.i); got != c.want {
+			t.Errorf("retryBackoff(%v, %d) = %v; 期望为 %v", c.backoff, c.i, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package browsers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/luoxk/chromedp"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"sync"
+	"time"
+)
+
+// screenshotAllConcurrency ScreenshotAll 同时截图的实例数量上限
+const screenshotAllConcurrency = 4
+
+const (
+	stitchMaxSteps = 50                     // StitchedScreenshot 最多滚动截图的次数，避免无限滚动的页面
+	stitchSettle   = 100 * time.Millisecond // 每次滚动后等待页面重绘的时间
+)
+
+// Screenshot 截取当前视口，返回 PNG 数据
+func (bi *BrowserInstance) Screenshot() ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+	var buf []byte
+	if err := chromedp.Run(bi.Ctx, chromedp.CaptureScreenshot(&buf)); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ScreenshotAll 并行截取所有未关闭实例的视口，按实例 ID 返回
+// 部分实例失败时仍返回成功的截图，失败原因合并到 error 中
+func (bc *BrowserController) ScreenshotAll() (map[int][]byte, error) {
+	bc.mu.Lock()
+	instances := make([]*BrowserInstance, 0, len(bc.instances))
+	for _, instance := range bc.instances {
+		if !instance.Closed() {
+			instances = append(instances, instance)
+		}
+	}
+	bc.mu.Unlock()
+
+	var mu sync.Mutex
+	shots := make(map[int][]byte, len(instances))
+	errs := make([]error, len(instances))
+	sem := make(chan struct{}, screenshotAllConcurrency)
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, instance *BrowserInstance) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			buf, err := instance.Screenshot()
+			if err != nil {
+				errs[i] = fmt.Errorf("browser instance %d: %w", instance.ID, err)
+				return
+			}
+			mu.Lock()
+			shots[instance.ID] = buf
+			mu.Unlock()
+		}(i, instance)
+	}
+	wg.Wait()
+	return shots, errors.Join(errs...)
+}
+
+// ScreenshotImage 截取当前视口并解码为 image.Image，便于直接裁剪或识别像素
+func (bi *BrowserInstance) ScreenshotImage() (image.Image, error) {
+	buf, err := bi.Screenshot()
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("解析截图失败: %v", err)
+	}
+	return img, nil
+}
+
+// StitchedScreenshot 按视口高度逐屏滚动截图，并将每屏新出现的部分纵向拼接成一张完整页面的 PNG
+// 用于将视口设为整页高度会失败的超长页面；最多截取 stitchMaxSteps 屏，结束后恢复原来的滚动位置
+// 固定在顶部的 fixed/sticky 元素（如导航栏）只在第一屏中保留，之后每屏跳过它们遮挡的区域
+func (bi *BrowserInstance) StitchedScreenshot() ([]byte, error) {
+	if bi.Closed() {
+		return nil, ErrBrowserClosed
+	}
+
+	var metrics struct {
+		Height   float64 `json:"height"`
+		Viewport float64 `json:"viewport"`
+		ScrollY  float64 `json:"scrollY"`
+		Header   float64 `json:"header"`
+	}
+	// header 为固定在视口顶部的 fixed/sticky 元素覆盖的高度，sticky 元素按它吸顶时的位置计算
+	err := bi.callJs(`({
+		height: Math.max(document.documentElement.scrollHeight, document.body ? document.body.scrollHeight : 0),
+		viewport: window.innerHeight,
+		scrollY: window.scrollY,
+		header: Array.from(document.querySelectorAll('body *')).reduce((h, el) => {
+			const s = getComputedStyle(el);
+			if (s.position !== 'fixed' && s.position !== 'sticky') return h;
+			const r = el.getBoundingClientRect();
+			const top = s.position === 'fixed' ? r.top : parseFloat(s.top);
+			if (!(top <= 1) || r.width <= 0 || r.height <= 0) return h;
+			return Math.max(h, top + r.height);
+		}, 0)
+	})`, &metrics)
+	if err != nil {
+		return nil, err
+	}
+	if metrics.Viewport <= 0 {
+		return nil, fmt.Errorf("视口高度无效: %v", metrics.Viewport)
+	}
+	defer bi.callJs(fmt.Sprintf(`window.scrollTo(0, %v)`, metrics.ScrollY), nil)
+
+	out, err := stitchScreens(metrics.Height, metrics.Viewport, metrics.Header, func(scrollTo float64) (float64, image.Image, error) {
+		var y float64
+		if err := bi.callJs(fmt.Sprintf(`window.scrollTo(0, %v); window.scrollY`, scrollTo), &y); err != nil {
+			return 0, nil, err
+		}
+		time.Sleep(stitchSettle)
+		shot, err := bi.ScreenshotImage()
+		return y, shot, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stitchScreens 逐屏调用 capture 滚动到指定位置并截图，拼接成高 height 的整页图片，单位为 CSS 像素
+// capture 返回实际的滚动位置，滚到底部时浏览器会限制滚动位置，实际位置可能小于请求的位置。
+// 从第二屏起让新内容出现在 header 之下，跳过顶部固定元素遮挡的区域，避免它们在拼接结果中重复
+func stitchScreens(height, viewport, header float64, capture func(scrollTo float64) (float64, image.Image, error)) (image.Image, error) {
+	if header >= viewport {
+		// 固定元素占满视口时无法跳过，按普通页面处理
+		header = 0
+	}
+
+	var canvas *image.RGBA
+	var scale float64 // 截图像素与 CSS 像素之比
+	covered := 0.0    // 已截取到的页面高度，CSS 像素
+	for step := 0; covered < height && step < stitchMaxSteps; step++ {
+		scrollTo := covered
+		if step > 0 {
+			scrollTo -= header
+		}
+		y, shot, err := capture(scrollTo)
+		if err != nil {
+			return nil, err
+		}
+		b := shot.Bounds()
+		if canvas == nil {
+			scale = float64(b.Dy()) / viewport
+			canvas = image.NewRGBA(image.Rect(0, 0, b.Dx(), int(math.Ceil(height*scale))))
+		}
+
+		// 只保留这一屏中 covered 以下新出现的部分，避免与上一屏重叠
+		skip := int(math.Round((covered - y) * scale))
+		dstY := int(math.Round(covered * scale))
+		dst := image.Rect(0, dstY, b.Dx(), dstY+b.Dy()-skip)
+		draw.Draw(canvas, dst, shot, image.Point{X: b.Min.X, Y: b.Min.Y + skip}, draw.Src)
+
+		next := y + viewport
+		if next <= covered {
+			// 页面无法继续滚动
+			break
+		}
+		covered = next
+	}
+	if canvas == nil {
+		return nil, fmt.Errorf("页面高度为 0")
+	}
+
+	if h := int(math.Round(covered * scale)); h < canvas.Bounds().Dy() {
+		return canvas.SubImage(image.Rect(0, 0, canvas.Bounds().Dx(), h)), nil
+	}
+	return canvas, nil
+}
+
+// ScreenshotDiff 截取当前视口并与 baseline（PNG）逐像素比较
+// 返回不同像素所占的百分比和标红差异的 PNG；尺寸不一致时差异为 100% 且不生成差异图
+func (bi *BrowserInstance) ScreenshotDiff(baseline []byte) (diffPercent float64, diffImage []byte, err error) {
+	base, err := png.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return 0, nil, fmt.Errorf("解析基准图失败: %v", err)
+	}
+	cur, err := bi.ScreenshotImage()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	percent, diff := diffImages(base, cur)
+	if diff == nil {
+		return percent, nil, nil
+	}
+	var out bytes.Buffer
+	if err = png.Encode(&out, diff); err != nil {
+		return 0, nil, err
+	}
+	return percent, out.Bytes(), nil
+}
+
+// diffImages 逐像素比较两张图，返回不同像素的百分比和差异图
+// 差异图中相同的像素淡化为灰色，不同的像素标为红色；尺寸不同时返回 100 和 nil
+func diffImages(a, b image.Image) (float64, *image.RGBA) {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Dx() != bb.Dx() || ab.Dy() != bb.Dy() {
+		return 100, nil
+	}
+	total := ab.Dx() * ab.Dy()
+	if total == 0 {
+		return 0, image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	diff := image.NewRGBA(image.Rect(0, 0, ab.Dx(), ab.Dy()))
+	changed := 0
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			ca := color.NRGBAModel.Convert(a.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			cb := color.NRGBAModel.Convert(b.At(bb.Min.X+x, bb.Min.Y+y)).(color.NRGBA)
+			if ca != cb {
+				changed++
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			gray := color.GrayModel.Convert(ca).(color.Gray)
+			// 淡化到浅灰，让红色差异更醒目
+			v := 192 + gray.Y/4
+			diff.Set(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	return float64(changed) * 100 / float64(total), diff
+}
@@ -0,0 +1,88 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/luoxk/chromedp"
+	"time"
+)
+
+const (
+	defaultCaptureHeight  = 800              // 只设置 Width 时使用的视口高度
+	defaultCaptureTimeout = 30 * time.Second // Timeout 为 0 时的默认总超时时间
+)
+
+// CaptureOptions ScreenshotService.Capture 的截图参数
+type CaptureOptions struct {
+	FullPage bool          // 截取整个页面，否则只截取视口
+	Width    int           // 视口宽度（CSS 像素），为 0 时使用实例当前视口
+	Height   int           // 视口高度，仅在设置了 Width 时生效，为 0 时使用 defaultCaptureHeight
+	WaitFor  LoadState     // 截图前等待的加载状态，为空时使用 LoadStateLoad
+	Timeout  time.Duration // 借出实例、导航和等待加载状态的总超时时间，为 0 时使用 defaultCaptureTimeout
+}
+
+// ScreenshotService 基于 BrowserPool 的 URL 截图服务，每次截图借出一个实例，完成后归还
+type ScreenshotService struct {
+	pool *BrowserPool
+}
+
+// NewScreenshotService 创建使用 pool 中实例截图的服务，pool 的生命周期由调用方管理
+func NewScreenshotService(pool *BrowserPool) *ScreenshotService {
+	return &ScreenshotService{pool: pool}
+}
+
+// Capture 打开 url，等待指定的加载状态后截图，返回 PNG 数据
+// 设置了 Width 时截图后会清除视口覆盖，避免影响下一个借用者
+func (s *ScreenshotService) Capture(url string, opts CaptureOptions) ([]byte, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultCaptureTimeout
+	}
+	waitFor := opts.WaitFor
+	if waitFor == "" {
+		waitFor = LoadStateLoad
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	inst, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.pool.Release(inst)
+
+	if opts.Width > 0 {
+		height := opts.Height
+		if height <= 0 {
+			height = defaultCaptureHeight
+		}
+		if err = inst.SetViewport(opts.Width, height, 0, false); err != nil {
+			return nil, err
+		}
+		defer chromedp.Run(inst.Ctx, emulation.ClearDeviceMetricsOverride())
+	}
+
+	// 导航和等待加载共用同一个截止时间，加载卡住的页面不会一直占用池中的实例
+	deadline, _ := ctx.Deadline()
+	navCtx, navCancel := context.WithDeadline(inst.Ctx, deadline)
+	err = inst.gotoCtx(navCtx, url, inst.options.Referer)
+	navCancel()
+	if err != nil {
+		// 中止仍在进行的加载，避免影响下一个借用者
+		inst.StopLoading()
+		return nil, err
+	}
+	if err = inst.WaitForLoadState(waitFor, time.Until(deadline)); err != nil {
+		return nil, err
+	}
+
+	if !opts.FullPage {
+		return inst.Screenshot()
+	}
+	var buf []byte
+	// quality 为 100 时输出 PNG
+	if err = chromedp.Run(inst.Ctx, chromedp.FullScreenshot(&buf, 100)); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
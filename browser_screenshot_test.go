@@ -0,0 +1,78 @@
+package browsers
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestDiffImages(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	b := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			a.Set(x, y, color.White)
+			b.Set(x, y, color.White)
+		}
+	}
+
+	if percent, diff := diffImages(a, b); percent != 0 || diff == nil {
+		t.Fatalf("相同图片的差异应为 0，实际为 %v", percent)
+	}
+
+	for x := 0; x < 10; x++ {
+		b.Set(x, 0, color.Black)
+	}
+	percent, diff := diffImages(a, b)
+	if percent != 10 {
+		t.Fatalf("差异应为 10%%，实际为 %v", percent)
+	}
+	if got := diff.RGBAAt(0, 0); got != (color.RGBA{R: 255, A: 255}) {
+		t.Fatalf("不同的像素应标红，实际为 %v", got)
+	}
+	if got := diff.RGBAAt(0, 1); got.R != got.G || got.G != got.B {
+		t.Fatalf("相同的像素应为灰色，实际为 %v", got)
+	}
+
+	if percent, diff := diffImages(a, image.NewRGBA(image.Rect(0, 0, 5, 10))); percent != 100 || diff != nil {
+		t.Fatalf("尺寸不同应返回 100%% 且没有差异图，实际为 %v", percent)
+	}
+}
+
+func TestStitchScreens_FixedHeaderOnce(t *testing.T) {
+	const height, viewport, header = 250, 100, 20
+	red := color.RGBA{R: 255, A: 255}
+	// 页面第 y 行的颜色，顶部 header 行被固定导航栏（红色）覆盖
+	row := func(y int) color.RGBA { return color.RGBA{G: uint8(y), B: 1, A: 255} }
+	capture := func(scrollTo float64) (float64, image.Image, error) {
+		y := int(math.Min(math.Max(scrollTo, 0), height-viewport))
+		shot := image.NewRGBA(image.Rect(0, 0, 1, viewport))
+		for r := 0; r < viewport; r++ {
+			if r < header {
+				shot.SetRGBA(0, r, red)
+			} else {
+				shot.SetRGBA(0, r, row(y+r))
+			}
+		}
+		return float64(y), shot, nil
+	}
+
+	out, err := stitchScreens(height, viewport, header, capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dy := out.Bounds().Dy(); dy != height {
+		t.Fatalf("拼接结果高度为 %d，期望为 %d", dy, height)
+	}
+	for y := 0; y < height; y++ {
+		got := color.RGBAModel.Convert(out.At(0, y)).(color.RGBA)
+		want := row(y)
+		if y < header {
+			want = red
+		}
+		if got != want {
+			t.Fatalf("第 %d 行为 %v，期望为 %v；固定导航栏只应出现在顶部一次", y, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package browsers
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// scriptFiles EvaluateFile 读取过的脚本，文件修改时间或大小变化后重新读取
+var scriptFiles = struct {
+	sync.Mutex
+	m map[string]cachedScript
+}{m: make(map[string]cachedScript)}
+
+type cachedScript struct {
+	modTime time.Time
+	size    int64
+	source  string
+}
+
+// EvaluateFile 读取 JS 文件并在页面中执行，结果反序列化到 out，out 为 nil 时忽略结果
+// 文件内容按路径缓存，修改后下一次调用会自动重新读取
+func (bi *BrowserInstance) EvaluateFile(path string, out interface{}) error {
+	source, err := readScript(path)
+	if err != nil {
+		return err
+	}
+	return bi.callJs(source, out)
+}
+
+// readScript 返回 path 的内容，文件未变化时直接使用缓存
+func readScript(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("读取脚本失败: %w", err)
+	}
+
+	scriptFiles.Lock()
+	cached, ok := scriptFiles.m[path]
+	scriptFiles.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.source, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取脚本失败: %w", err)
+	}
+	scriptFiles.Lock()
+	scriptFiles.m[path] = cachedScript{modTime: info.ModTime(), size: info.Size(), source: string(b)}
+	scriptFiles.Unlock()
+	return string(b), nil
+}
@@ -0,0 +1,34 @@
+package browsers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.js")
+	if err := os.WriteFile(path, []byte("1 + 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readScript(path); err != nil || got != "1 + 1" {
+		t.Fatalf("readScript = %q, %v", got, err)
+	}
+
+	// 修改内容和修改时间后应重新读取
+	if err := os.WriteFile(path, []byte("2 + 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readScript(path); err != nil || got != "2 + 2" {
+		t.Fatalf("文件修改后 readScript = %q, %v", got, err)
+	}
+
+	if _, err := readScript(filepath.Join(t.TempDir(), "missing.js")); err == nil {
+		t.Fatal("文件不存在时应返回错误")
+	}
+}
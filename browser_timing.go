@@ -0,0 +1,63 @@
+package browsers
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timing 最近一次导航的耗时，DOMContentLoaded 和 Load 从导航开始计算
+// 对应阶段尚未完成时为 0
+type Timing struct {
+	DNS              time.Duration // DNS 解析
+	Connect          time.Duration // 建立 TCP/TLS 连接
+	TTFB             time.Duration // 从发出请求到收到首字节
+	DOMContentLoaded time.Duration // DOMContentLoaded 事件结束
+	Load             time.Duration // load 事件结束
+}
+
+// navigationTimingJS 优先读取 PerformanceNavigationTiming，不支持时退回 performance.timing
+// 返回值均为毫秒
+const navigationTimingJS = `(function() {
+	var t = performance.getEntriesByType && performance.getEntriesByType("navigation")[0];
+	var start = 0;
+	if (!t) {
+		t = performance.timing;
+		start = t.navigationStart;
+	}
+	function since(v) { return v > 0 ? v - start : 0; }
+	return {
+		dns: t.domainLookupEnd - t.domainLookupStart,
+		connect: t.connectEnd - t.connectStart,
+		ttfb: t.responseStart > 0 ? t.responseStart - t.requestStart : 0,
+		domContentLoaded: since(t.domContentLoadedEventEnd),
+		load: since(t.loadEventEnd)
+	};
+})()`
+
+// NavigationTiming 读取当前页面最近一次导航的 DNS、连接、首字节、DOMContentLoaded 和 load 耗时
+func (bi *BrowserInstance) NavigationTiming() (*Timing, error) {
+	var ms struct {
+		DNS              float64 `json:"dns"`
+		Connect          float64 `json:"connect"`
+		TTFB             float64 `json:"ttfb"`
+		DOMContentLoaded float64 `json:"domContentLoaded"`
+		Load             float64 `json:"load"`
+	}
+	if err := bi.callJs(navigationTimingJS, &ms); err != nil {
+		return nil, fmt.Errorf("读取导航耗时失败: %w", err)
+	}
+	return &Timing{
+		DNS:              msDuration(ms.DNS),
+		Connect:          msDuration(ms.Connect),
+		TTFB:             msDuration(ms.TTFB),
+		DOMContentLoaded: msDuration(ms.DOMContentLoaded),
+		Load:             msDuration(ms.Load),
+	}, nil
+}
+
+func msDuration(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
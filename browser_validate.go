@@ -0,0 +1,113 @@
+package browsers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ValidateOptions 在不启动浏览器的情况下检查 options：Path 存在且可执行、Proxy 格式正确、
+// UserDir 可写、WindowSize 为正数、Env 为 KEY=value 格式，所有问题合并后返回
+// 设置了 RemoteURL 时只检查 RemoteURL 本身
+func ValidateOptions(options BrowserOptions) error {
+	var errs []error
+
+	if options.RemoteURL != "" {
+		u, err := url.Parse(options.RemoteURL)
+		if err != nil || u.Host == "" || (u.Scheme != "ws" && u.Scheme != "wss" && u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf("RemoteURL 格式错误: %q", options.RemoteURL))
+		}
+		return errors.Join(errs...)
+	}
+
+	if options.Path != "" {
+		if err := checkExecutable(options.Path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if options.Proxy != "" {
+		if err := checkProxy(options.Proxy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if options.UserDir != "" {
+		if err := checkWritableDir(options.UserDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if options.WindowSize != nil && (options.WindowSize.X <= 0 || options.WindowSize.Y <= 0) {
+		errs = append(errs, fmt.Errorf("WindowSize 必须为正数: %dx%d", options.WindowSize.X, options.WindowSize.Y))
+	}
+	if err := validateEnv(options.Env); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// checkExecutable 检查 path 是否为可执行文件，Windows 上不检查权限位
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("浏览器路径不可用: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("浏览器路径是目录: %s", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("浏览器路径不可执行: %s", path)
+	}
+	return nil
+}
+
+// checkProxy 检查代理地址，支持 host:port 或带 http、https、socks4、socks5 协议的写法
+func checkProxy(proxy string) error {
+	raw := proxy
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("代理地址格式错误: %q", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks4", "socks5":
+		return nil
+	default:
+		return fmt.Errorf("不支持的代理协议 %s: %q", u.Scheme, proxy)
+	}
+}
+
+// checkWritableDir 检查 dir 可写；目录尚不存在时检查最近一级已存在的上级目录
+func checkWritableDir(dir string) error {
+	target := dir
+	for {
+		info, err := os.Stat(target)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("用户目录不是目录: %s", target)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("用户目录不可用: %w", err)
+		}
+		parent := filepath.Dir(target)
+		if parent == target {
+			return fmt.Errorf("用户目录不可用: %s", dir)
+		}
+		target = parent
+	}
+
+	// 权限位不能反映 ACL、只读挂载等情况，直接尝试创建文件
+	f, err := os.CreateTemp(target, ".browsers-validate-*")
+	if err != nil {
+		return fmt.Errorf("用户目录不可写: %w", err)
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
@@ -0,0 +1,47 @@
+package browsers
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateOptions(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "chrome")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ok := BrowserOptions{
+		Path:       exe,
+		Proxy:      "socks5://127.0.0.1:1080",
+		UserDir:    filepath.Join(dir, "profile", "new"),
+		WindowSize: &image.Point{X: 1280, Y: 720},
+		Env:        []string{"DISPLAY=:99"},
+	}
+	if err := ValidateOptions(ok); err != nil {
+		t.Fatalf("合法的参数不应返回错误: %v", err)
+	}
+	if err := ValidateOptions(BrowserOptions{Proxy: "127.0.0.1:8080"}); err != nil {
+		t.Fatalf("host:port 形式的代理应合法: %v", err)
+	}
+
+	bad := BrowserOptions{
+		Path:       filepath.Join(dir, "missing"),
+		Proxy:      "ftp://127.0.0.1:21",
+		UserDir:    exe,
+		WindowSize: &image.Point{X: 0, Y: 720},
+		Env:        []string{"DISPLAY"},
+	}
+	err := ValidateOptions(bad)
+	if err == nil {
+		t.Fatal("非法的参数应返回错误")
+	}
+	// 所有问题都应合并返回
+	if n := len(strings.Split(err.Error(), "\n")); n != 5 {
+		t.Fatalf("应返回 5 个问题，实际为 %d: %v", n, err)
+	}
+}
@@ -0,0 +1,293 @@
+package browsers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pollInterval 轮询类等待方法的检查间隔
+const pollInterval = 100 * time.Millisecond
+
+// networkIdleWindow 没有进行中的请求持续多久视为网络空闲，与 Playwright 一致
+const networkIdleWindow = 500 * time.Millisecond
+
+// mutationGrace WaitForMutation 在页面内计时的基础上额外留出的 CDP 往返时间
+const mutationGrace = time.Second
+
+// LoadState 页面加载状态
+type LoadState string
+
+const (
+	LoadStateDOMContentLoaded LoadState = "domcontentloaded" // DOM 解析完成
+	LoadStateLoad             LoadState = "load"             // 页面及其资源加载完成
+	LoadStateNetworkIdle      LoadState = "networkidle"      // load 之后网络空闲 500ms
+)
+
+// WaitForLoadState 等待页面达到指定的加载状态，超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForLoadState(state LoadState, timeout time.Duration) error {
+	switch state {
+	case LoadStateDOMContentLoaded:
+		return bi.waitReadyState(timeout, "interactive", "complete")
+	case LoadStateLoad:
+		return bi.waitReadyState(timeout, "complete")
+	case LoadStateNetworkIdle:
+		deadline := time.Now().Add(timeout)
+		if err := bi.waitReadyState(timeout, "complete"); err != nil {
+			return err
+		}
+		return bi.waitNetworkIdle(time.Until(deadline))
+	}
+	return fmt.Errorf("未知的加载状态: %s", state)
+}
+
+// waitReadyState 轮询 document.readyState 直到等于 states 之一
+func (bi *BrowserInstance) waitReadyState(timeout time.Duration, states ...string) error {
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var readyState string
+		if err := chromedp.Evaluate(`document.readyState`, &readyState).Do(ctx); err != nil {
+			return false, err
+		}
+		for _, s := range states {
+			if readyState == s {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// waitNetworkIdle 统计进行中的请求，直到连续 networkIdleWindow 没有任何请求
+func (bi *BrowserInstance) waitNetworkIdle(timeout time.Duration) error {
+	var (
+		mu           sync.Mutex
+		inflight     = make(map[network.RequestID]struct{})
+		lastActivity = time.Now()
+	)
+	lctx, cancel := context.WithCancel(bi.Ctx)
+	defer cancel()
+	chromedp.ListenTarget(lctx, func(ev interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			inflight[ev.RequestID] = struct{}{}
+		case *network.EventLoadingFinished:
+			delete(inflight, ev.RequestID)
+		case *network.EventLoadingFailed:
+			delete(inflight, ev.RequestID)
+		default:
+			return
+		}
+		lastActivity = time.Now()
+	})
+
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(inflight) == 0 && time.Since(lastActivity) >= networkIdleWindow, nil
+	})
+}
+
+// poll 每隔 pollInterval 检查一次 cond，直到返回 true 或超时
+// cond 返回的错误视为暂未满足（例如导航过程中执行上下文被销毁），超时时一并返回最后一次错误
+func (bi *BrowserInstance) poll(timeout time.Duration, cond func(ctx context.Context) (bool, error)) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			ok, err := cond(ctx)
+			if ok {
+				return nil
+			}
+			if err != nil && ctx.Err() == nil {
+				lastErr = err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}))
+	if err != nil && ctx.Err() == context.DeadlineExceeded && bi.Ctx.Err() == nil {
+		if lastErr != nil {
+			return fmt.Errorf("%w: %v", ErrWaitTimeout, lastErr)
+		}
+		return ErrWaitTimeout
+	}
+	return err
+}
+
+// WaitForCount 等待选择器匹配的元素数量恰好等于 count，超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForCount(sel string, count int, timeout time.Duration) error {
+	return bi.waitForCount(sel, timeout, func(n int) bool { return n == count })
+}
+
+// WaitForCountAtLeast 等待选择器匹配的元素数量不少于 count，适合无限滚动等逐步加载的列表
+func (bi *BrowserInstance) WaitForCountAtLeast(sel string, count int, timeout time.Duration) error {
+	return bi.waitForCount(sel, timeout, func(n int) bool { return n >= count })
+}
+
+func (bi *BrowserInstance) waitForCount(sel string, timeout time.Duration, ok func(n int) bool) error {
+	expr := fmt.Sprintf(`document.querySelectorAll(%s).length`, jsString(sel))
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var n int
+		if err := chromedp.Evaluate(expr, &n).Do(ctx); err != nil {
+			return false, err
+		}
+		return ok(n), nil
+	})
+}
+
+// WaitForTitle 等待 document.title 包含 substr，超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForTitle(substr string, timeout time.Duration) error {
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var title string
+		if err := chromedp.Evaluate(`document.title`, &title).Do(ctx); err != nil {
+			return false, err
+		}
+		return strings.Contains(title, substr), nil
+	})
+}
+
+// WaitForText 等待选择器匹配到的第一个元素的 innerText 包含 substr，元素不存在时继续等待
+// 超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForText(sel, substr string, timeout time.Duration) error {
+	expr := fmt.Sprintf(`(function() {
+		var el = document.querySelector(%s);
+		return el ? el.innerText : null;
+	})()`, jsString(sel))
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var text *string
+		if err := chromedp.Evaluate(expr, &text).Do(ctx); err != nil {
+			return false, err
+		}
+		return text != nil && strings.Contains(*text, substr), nil
+	})
+}
+
+// WaitForValue 轮询 JS 表达式直到结果不为 null 或 undefined，返回该结果
+// 字符串结果原样返回，其他类型返回其 JSON 文本；超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForValue(expr string, timeout time.Duration) (string, error) {
+	var value string
+	err := bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var raw []byte
+		if err := chromedp.Evaluate(expr, &raw).Do(ctx); err != nil {
+			return false, err
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			return false, nil
+		}
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			value = s
+		} else {
+			value = string(raw)
+		}
+		return true, nil
+	})
+	return value, err
+}
+
+// WaitForImages 等待页面中所有 <img> 加载完成（complete 且 naturalWidth > 0），适合在截图前调用
+// 加载失败的图片会一直等待到超时，超时返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitForImages(timeout time.Duration) error {
+	const expr = `Array.prototype.every.call(document.images, function(img) {
+		return img.complete && img.naturalWidth > 0;
+	})`
+	return bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var done bool
+		if err := chromedp.Evaluate(expr, &done).Do(ctx); err != nil {
+			return false, err
+		}
+		return done, nil
+	})
+}
+
+// interstitialJS 判断当前页面是否为 Cloudflare 等 JS 验证的过渡页
+const interstitialJS = `(function() {
+	if (!document.body || document.readyState === "loading") {
+		return true;
+	}
+	var title = document.title.toLowerCase();
+	var titles = ["just a moment", "attention required", "checking your browser", "请稍候…"];
+	for (var i = 0; i < titles.length; i++) {
+		if (title.indexOf(titles[i]) !== -1) {
+			return true;
+		}
+	}
+	return document.querySelector("#challenge-form, #challenge-running, #cf-challenge-running, " +
+		".cf-browser-verification, #cf-please-wait, iframe[src*='challenges.cloudflare.com']") !== null;
+})()`
+
+// WaitPastInterstitial 等待 Cloudflare 等验证过渡页结束并加载出真实内容
+// 当前不是过渡页时立即返回，超时后仍停留在过渡页返回 ErrWaitTimeout
+func (bi *BrowserInstance) WaitPastInterstitial(timeout time.Duration) error {
+	err := bi.poll(timeout, func(ctx context.Context) (bool, error) {
+		var blocked bool
+		if err := chromedp.Evaluate(interstitialJS, &blocked).Do(ctx); err != nil {
+			return false, err
+		}
+		return !blocked, nil
+	})
+	if errors.Is(err, ErrWaitTimeout) {
+		return fmt.Errorf("%w: 页面仍停留在验证页", err)
+	}
+	return err
+}
+
+// WaitForMutation 等待选择器匹配到的节点发生 DOM 变化（子节点、属性或文本）
+func (bi *BrowserInstance) WaitForMutation(sel string, timeout time.Duration) error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	// 通过 SabaFetch 的 await 机制等待 Promise 完成，无论成功或超时都断开 observer
+	// 不走 SabaFetch 的操作锁，避免等待期间阻塞实例上的其他操作
+	eval := fmt.Sprintf(`await new Promise(function(resolve) {
+		var el = document.querySelector(%s);
+		if (!el) {
+			resolve({"error": "no such element"});
+			return;
+		}
+		var timer = null;
+		var observer = new MutationObserver(function() {
+			observer.disconnect();
+			clearTimeout(timer);
+			resolve({"data": "mutated"});
+		});
+		observer.observe(el, {childList: true, attributes: true, characterData: true, subtree: true});
+		timer = setTimeout(function() {
+			observer.disconnect();
+			resolve({"error": "timeout"});
+		}, %d);
+	})`, jsString(sel), timeout.Milliseconds())
+
+	ctx, cancel := context.WithTimeout(bi.Ctx, timeout+mutationGrace)
+	defer cancel()
+	resp := evalFetch(ctx, eval, "")
+	switch resp.Error {
+	case "":
+		return nil
+	case "no such element":
+		return fmt.Errorf("%w: %s", ErrNoSuchElement, sel)
+	case "timeout":
+		return fmt.Errorf("%w: 等待 DOM 变化: %s", ErrWaitTimeout, sel)
+	}
+	return resp.Err()
+}
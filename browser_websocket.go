@@ -0,0 +1,115 @@
+package browsers
+
+import (
+	"context"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/luoxk/chromedp"
+	"sync"
+	"time"
+)
+
+// WSDirection WebSocket 帧的方向
+type WSDirection string
+
+const (
+	WSSent     WSDirection = "sent"     // 页面发出
+	WSReceived WSDirection = "received" // 页面收到
+)
+
+// WSFrame 一个 WebSocket 帧
+type WSFrame struct {
+	URL       string      // WebSocket 连接地址
+	Direction WSDirection // 帧方向
+	Opcode    int         // 1 为文本帧，2 为二进制帧
+	Payload   string      // 文本帧为原文，二进制帧为 base64 编码
+	Timestamp time.Time   // 帧的时间
+}
+
+// wsCapture 保存 WebSocket 捕获状态
+type wsCapture struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	urls   map[network.RequestID]string
+	frames []WSFrame
+}
+
+// StartWSCapture 开始记录页面的 WebSocket 帧，重复调用会清空之前的记录
+func (bi *BrowserInstance) StartWSCapture() error {
+	if bi.Closed() {
+		return ErrBrowserClosed
+	}
+
+	ctx, cancel := context.WithCancel(bi.Ctx)
+	capture := &wsCapture{
+		cancel: cancel,
+		urls:   make(map[network.RequestID]string),
+	}
+	chromedp.ListenTarget(ctx, capture.handle)
+
+	bi.wsMu.Lock()
+	old := bi.ws
+	bi.ws = capture
+	bi.wsMu.Unlock()
+
+	if old != nil {
+		old.cancel()
+	}
+	return nil
+}
+
+// StopWSCapture 停止记录 WebSocket 帧，已记录的帧仍可通过 WSFrames 获取
+func (bi *BrowserInstance) StopWSCapture() {
+	bi.wsMu.Lock()
+	defer bi.wsMu.Unlock()
+	if bi.ws != nil {
+		bi.ws.cancel()
+	}
+}
+
+// WSFrames 返回已记录的 WebSocket 帧的副本，按时间顺序排列
+func (bi *BrowserInstance) WSFrames() []WSFrame {
+	bi.wsMu.Lock()
+	capture := bi.ws
+	bi.wsMu.Unlock()
+	if capture == nil {
+		return nil
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	return append([]WSFrame(nil), capture.frames...)
+}
+
+func (c *wsCapture) handle(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventWebSocketCreated:
+		c.urls[ev.RequestID] = ev.URL
+	case *network.EventWebSocketClosed:
+		delete(c.urls, ev.RequestID)
+	case *network.EventWebSocketFrameSent:
+		c.add(ev.RequestID, WSSent, ev.Timestamp, ev.Response)
+	case *network.EventWebSocketFrameReceived:
+		c.add(ev.RequestID, WSReceived, ev.Timestamp, ev.Response)
+	}
+}
+
+func (c *wsCapture) add(id network.RequestID, dir WSDirection, timestamp *cdp.MonotonicTime, frame *network.WebSocketFrame) {
+	if frame == nil {
+		return
+	}
+	ts := time.Now()
+	if timestamp != nil {
+		ts = timestamp.Time()
+	}
+	c.frames = append(c.frames, WSFrame{
+		URL:       c.urls[id],
+		Direction: dir,
+		Opcode:    int(frame.Opcode),
+		Payload:   frame.PayloadData,
+		Timestamp: ts,
+	})
+}
@@ -4,16 +4,16 @@ go 1.23
 
 require (
 	github.com/chromedp/cdproto v0.0.0-20250210231439-aea867ea8506
+	github.com/gobwas/ws v1.4.0
 	github.com/luoxk/chromedp v0.12.1
+	github.com/mailru/easyjson v0.9.0
 )
 
 require (
 	github.com/chromedp/sysutil v1.1.0 // indirect
 	github.com/gobwas/httphead v0.1.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
-	github.com/gobwas/ws v1.4.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
-	github.com/mailru/easyjson v0.9.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 )
 
@@ -1,6 +1,12 @@
 package browsers
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func TestBrowserController_LaunchBrowser(t *testing.T) {
 	controller := NewBrowserController()
@@ -18,3 +24,73 @@ func TestBrowserController_LaunchBrowser(t *testing.T) {
 	controller.CloseAllBrowsers()
 
 }
+
+func TestBrowserController_CloseBrowserRacesMonitor(t *testing.T) {
+	controller := NewBrowserController()
+
+	for i := 0; i < 20; i++ {
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		instance := NewBrowserInstance(i, nil, ctx, func() {
+			calls.Add(1)
+			cancelCtx()
+		})
+		controller.mu.Lock()
+		controller.instances[i] = instance
+		controller.mu.Unlock()
+
+		// 上下文结束触发 monitorContext 关闭，同时显式 CloseBrowser
+		go cancelCtx()
+		if err := controller.CloseBrowser(i); err != nil {
+			t.Fatal(err)
+		}
+		if err := controller.CloseBrowser(i); err == nil {
+			t.Fatal("重复 CloseBrowser 应返回实例不存在的错误")
+		}
+
+		// 等待 monitorContext 执行完毕
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) && calls.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if n := calls.Load(); n != 1 {
+			t.Fatalf("实例 %d 的 Cancel 应只调用一次，实际调用 %d 次", i, n)
+		}
+	}
+	if n := controller.GetBrowserCount(); n != 0 {
+		t.Fatalf("关闭后实例数量应为 0，实际为 %d", n)
+	}
+}
+
+func TestBrowserController_Shutdown(t *testing.T) {
+	controller := NewBrowserController()
+	ctx, cancel := context.WithCancel(context.Background())
+	instance := NewBrowserInstance(1, nil, ctx, cancel)
+	controller.mu.Lock()
+	controller.instances[1] = instance
+	controller.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() { done <- controller.Shutdown(context.Background()) }()
+
+	// Shutdown 开始后不再接受新的启动
+	time.Sleep(50 * time.Millisecond)
+	if _, err := controller.LaunchBrowser(BrowserOptions{}); !errors.Is(err, ErrDraining) {
+		t.Fatalf("排空期间 LaunchBrowser 应返回 ErrDraining，实际为 %v", err)
+	}
+
+	// 实例自行结束后 Shutdown 返回
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("所有实例关闭后 Shutdown 应返回")
+	}
+	if n := controller.GetBrowserCount(); n != 0 {
+		t.Fatalf("Shutdown 后实例数量应为 0，实际为 %d", n)
+	}
+}